@@ -0,0 +1,166 @@
+package flogger
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// AsyncPolicy decides what an asynchronous logger does when its buffer is full.
+type AsyncPolicy int
+
+const (
+	// AsyncBlock makes the logging call wait until the buffer has room (the default), so no entry is lost.
+	AsyncBlock AsyncPolicy = iota
+	// AsyncDrop discards the entry instead of waiting, so logging never blocks the caller.
+	AsyncDrop
+)
+
+// asyncWriter hands formatted entries to a background goroutine that writes them to the underlying writer.
+type asyncWriter struct {
+	// out is the writer the background goroutine writes to.
+	out io.Writer
+	// policy decides whether Write blocks or drops when lines is full.
+	policy AsyncPolicy
+	// lines buffers the formatted entries waiting to be written.
+	lines chan []byte
+	// flushes receives flush requests; the background goroutine closes the channel once everything queued is written.
+	flushes chan chan struct{}
+	// done is closed when the background goroutine has exited.
+	done chan struct{}
+	// dropped counts the entries discarded by AsyncDrop.
+	dropped atomic.Uint64
+
+	// mu guards closed; Write holds it for reading so Close cannot close lines under a pending send.
+	mu sync.RWMutex
+	// closed reports whether Close was called.
+	closed bool
+}
+
+// newAsyncWriter creates an asyncWriter with a buffer for bufferSize entries and starts its background goroutine.
+func newAsyncWriter(out io.Writer, bufferSize int, policy AsyncPolicy) *asyncWriter {
+	w := &asyncWriter{
+		out:     out,
+		policy:  policy,
+		lines:   make(chan []byte, bufferSize),
+		flushes: make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write queues a copy of p (logrus reuses its buffers) for the background goroutine.
+// Once the writer is closed, p is written synchronously instead.
+func (w *asyncWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return w.out.Write(p)
+	}
+
+	line := make([]byte, len(p))
+	copy(line, p)
+
+	if w.policy == AsyncDrop {
+		select {
+		case w.lines <- line:
+		default:
+			w.dropped.Add(1)
+		}
+		return len(p), nil
+	}
+
+	w.lines <- line
+	return len(p), nil
+}
+
+// Flush blocks until every entry queued before the call has been written.
+func (w *asyncWriter) Flush() error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	// After Close everything has already been written.
+	if w.closed {
+		return nil
+	}
+
+	ack := make(chan struct{})
+	w.flushes <- ack
+	<-ack
+	return nil
+}
+
+// Close writes every queued entry and stops the background goroutine. It is safe to call more than once.
+func (w *asyncWriter) Close() error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.lines)
+	}
+	w.mu.Unlock()
+
+	<-w.done
+	return nil
+}
+
+// run writes queued entries until lines is closed, serving flush requests in between.
+func (w *asyncWriter) run() {
+	defer close(w.done)
+
+	for {
+		select {
+		case line, ok := <-w.lines:
+			if !ok {
+				return
+			}
+			_, _ = w.out.Write(line)
+		case ack := <-w.flushes:
+			w.drain()
+			close(ack)
+		}
+	}
+}
+
+// drain writes every entry currently queued without waiting for new ones.
+func (w *asyncWriter) drain() {
+	for {
+		select {
+		case line, ok := <-w.lines:
+			if !ok {
+				return
+			}
+			_, _ = w.out.Write(line)
+		default:
+			return
+		}
+	}
+}
+
+// EnableAsync moves writing entries off the caller's goroutine: entries are queued in a buffer of bufferSize entries
+// and written by a background goroutine. Formatting still happens on the caller's goroutine, so caller information
+// stays accurate. What happens when the buffer is full is decided by SetAsyncPolicy (blocking by default).
+// A bufferSize of zero or less turns asynchronous writing off again. Call Flush before exiting to write pending entries.
+func (l *Logger) EnableAsync(bufferSize int) {
+	if bufferSize < 0 {
+		bufferSize = 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.asyncSize = bufferSize
+	l.applyOutputs()
+}
+
+// SetAsyncPolicy decides what happens when the asynchronous buffer is full, see AsyncBlock and AsyncDrop.
+func (l *Logger) SetAsyncPolicy(policy AsyncPolicy) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.asyncPolicy = policy
+	if l.asyncSize > 0 {
+		l.applyOutputs()
+	}
+}
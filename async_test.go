@@ -0,0 +1,32 @@
+package flogger
+
+import (
+	"io"
+	"testing"
+)
+
+// benchmarkOutput measures Info calls of l writing to io.Discard, including writing the queued entries at the end.
+func benchmarkOutput(b *testing.B, l *Logger) {
+	l.SetOutput(io.Discard)
+	b.Cleanup(func() { _ = l.Close() })
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info("request %d done in %s", i, "5ms")
+	}
+	if err := l.Flush(); err != nil {
+		b.Fatal(err)
+	}
+}
+
+// BenchmarkInfoSync measures Info calls writing on the caller's goroutine, the baseline of BenchmarkInfoAsync.
+func BenchmarkInfoSync(b *testing.B) {
+	benchmarkOutput(b, New())
+}
+
+// BenchmarkInfoAsync measures Info calls queueing their entries for the background goroutine of EnableAsync.
+func BenchmarkInfoAsync(b *testing.B) {
+	l := New()
+	l.EnableAsync(1024)
+	benchmarkOutput(b, l)
+}
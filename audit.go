@@ -0,0 +1,54 @@
+package flogger
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"io"
+)
+
+// AuditField marks the entries of the audit stream, so they are recognizable wherever they end up.
+const AuditField = "audit"
+
+// newAuditLogger creates the logrus logger of the audit stream writing to w. It has none of flogger's hooks and
+// formatter options, so the main logger's configuration never affects it: entries are always JSON at Info level.
+func newAuditLogger(w io.Writer) *logrus.Logger {
+	return &logrus.Logger{
+		Out:       w,
+		Formatter: &logrus.JSONFormatter{},
+		Hooks:     make(logrus.LevelHooks),
+		Level:     logrus.InfoLevel,
+		ExitFunc:  func(int) {},
+	}
+}
+
+// SetAuditOutput sets the writer of the audit stream AuditLog writes to, e.g. an append-only file opened with
+// os.O_APPEND. A nil w disables the audit stream, which is how a Logger starts.
+func (l *Logger) SetAuditOutput(w io.Writer) {
+	if w == nil {
+		l.audit.Store(nil)
+		return
+	}
+	l.audit.Store(newAuditLogger(w))
+}
+
+// AuditLog writes a message to the audit stream set with SetAuditOutput, as a JSON entry at Info level with
+// "audit":true and the logger's child fields. It is separate from the main output: it bypasses level filtering,
+// sampling and deduplication, and neither the format, hooks nor default fields of the logger apply. Nothing is
+// written while no audit output is set.
+func (l *Logger) AuditLog(format string, args ...interface{}) {
+	audit := l.audit.Load()
+	if audit == nil {
+		return
+	}
+
+	msg := format
+	if len(args) > 0 {
+		msg = fmt.Sprintf(format, args...)
+	}
+	fields := make(logrus.Fields, len(l.fields)+1)
+	for k, v := range l.fields {
+		fields[k] = v
+	}
+	fields[AuditField] = true
+	audit.WithFields(fields).Info(msg)
+}
@@ -0,0 +1,148 @@
+package flogger
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// batchAttempts is how many times a BatchWriter hands a batch to its sink before dropping it.
+const batchAttempts = 3
+
+// batchMaxErrors is how many errors of dropped batches a BatchWriter keeps until the next Flush or Close;
+// later ones are only counted, so a sink that stays down does not grow memory without bound.
+const batchMaxErrors = 10
+
+// BatchWriter buffers written lines and hands them to a sink in batches, e.g. to ship many entries in one HTTP request.
+// A batch is sent once it holds maxBatch lines or flushInterval after its first line, whichever comes first.
+// If the sink returns an error, the batch is retried up to two more times and then dropped; the errors are
+// reported by the next Flush or Close, the first ten of them in full and the rest as a count.
+// It is safe for concurrent use.
+type BatchWriter struct {
+	// sink receives every batch.
+	sink func([][]byte) error
+	// maxBatch is the number of lines that triggers sending the batch.
+	maxBatch int
+	// flushInterval is the longest a line waits before its batch is sent.
+	flushInterval time.Duration
+
+	// mu guards the fields below and serializes the calls to sink.
+	mu sync.Mutex
+	// lines is the current batch.
+	lines [][]byte
+	// timer sends the current batch once flushInterval elapses, nil while the batch is empty.
+	timer *time.Timer
+	// errs collects the errors of the first dropped batches until the next Flush or Close, see batchMaxErrors.
+	errs []error
+	// moreErrs counts the dropped batches whose error did not fit in errs.
+	moreErrs int
+	// closed makes every write send its line right away.
+	closed bool
+}
+
+// NewBatchWriter creates a BatchWriter sending batches of up to maxBatch lines to sink, at least every flushInterval.
+// A maxBatch of zero or less sends one line per batch, and a flushInterval of zero or less only sends full batches.
+func NewBatchWriter(sink func([][]byte) error, maxBatch int, flushInterval time.Duration) *BatchWriter {
+	return &BatchWriter{
+		sink:          sink,
+		maxBatch:      max(maxBatch, 1),
+		flushInterval: flushInterval,
+	}
+}
+
+// Write adds a copy of p to the current batch, sending it if it is full. It never fails, sink errors are
+// reported by Flush and Close instead.
+func (w *BatchWriter) Write(p []byte) (int, error) {
+	// The logger reuses its buffer, so keep a copy.
+	line := append([]byte(nil), p...)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.lines = append(w.lines, line)
+	if w.closed || len(w.lines) >= w.maxBatch {
+		w.send()
+	} else if w.timer == nil && w.flushInterval > 0 {
+		w.timer = time.AfterFunc(w.flushInterval, w.timedFlush)
+	}
+	return len(p), nil
+}
+
+// Flush sends the current batch and returns the errors of the batches dropped since the last Flush or Close.
+func (w *BatchWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.send()
+	return w.takeErrors()
+}
+
+// Close sends the current batch and returns like Flush. Later writes are sent right away, one line per batch.
+// It is safe to call more than once.
+func (w *BatchWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	w.send()
+	return w.takeErrors()
+}
+
+// timedFlush sends the current batch once the flush interval has elapsed.
+func (w *BatchWriter) timedFlush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.send()
+}
+
+// send hands the current batch to the sink, retrying failed attempts, and starts a new batch. The caller must hold w.mu.
+func (w *BatchWriter) send() {
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	if len(w.lines) == 0 {
+		return
+	}
+
+	batch := w.lines
+	w.lines = nil
+
+	var err error
+	for attempt := 0; attempt < batchAttempts; attempt++ {
+		if err = w.sink(batch); err == nil {
+			return
+		}
+	}
+	if len(w.errs) < batchMaxErrors {
+		w.errs = append(w.errs, err)
+	} else {
+		w.moreErrs++
+	}
+}
+
+// takeErrors returns the collected errors joined, with the count of the ones not kept, and clears them.
+// The caller must hold w.mu.
+func (w *BatchWriter) takeErrors() error {
+	errs := w.errs
+	if w.moreErrs > 0 {
+		errs = append(errs, fmt.Errorf("flogger: %d more batches dropped", w.moreErrs))
+	}
+	w.errs = nil
+	w.moreErrs = 0
+	return errors.Join(errs...)
+}
+
+// SetBatchOutput redirects this logger's output to a BatchWriter for sink, see NewBatchWriter for the parameters.
+// Close sends the remaining lines.
+func (l *Logger) SetBatchOutput(sink func([][]byte) error, maxBatch int, flushInterval time.Duration) {
+	w := NewBatchWriter(sink, maxBatch, flushInterval)
+	l.SetOutput(w)
+
+	// The logger created the writer, so it is the one closing it, see Close.
+	l.mu.Lock()
+	l.files = append(l.files, w)
+	l.mu.Unlock()
+}
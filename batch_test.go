@@ -0,0 +1,52 @@
+package flogger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBatchWriterBatches(t *testing.T) {
+	var batches [][][]byte
+	w := NewBatchWriter(func(batch [][]byte) error {
+		batches = append(batches, batch)
+		return nil
+	}, 2, 0)
+
+	for _, line := range []string{"a\n", "b\n", "c\n"} {
+		_, _ = w.Write([]byte(line))
+	}
+	if len(batches) != 1 || len(batches[0]) != 2 {
+		t.Fatalf("batches before Flush = %q, want one full batch", batches)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 2 || string(batches[1][0]) != "c\n" {
+		t.Errorf("batches after Flush = %q, want the remaining line sent", batches)
+	}
+}
+
+func TestBatchWriterCapsErrors(t *testing.T) {
+	sinkErr := errors.New("sink down")
+	w := NewBatchWriter(func([][]byte) error { return sinkErr }, 1, 0)
+
+	const dropped = 1000
+	for i := 0; i < dropped; i++ {
+		_, _ = w.Write([]byte("line\n"))
+	}
+	if len(w.errs) != batchMaxErrors {
+		t.Errorf("kept %d errors, want %d", len(w.errs), batchMaxErrors)
+	}
+
+	err := w.Flush()
+	if !errors.Is(err, sinkErr) {
+		t.Errorf("Flush error %v, want the sink's", err)
+	}
+	if want := "990 more batches dropped"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Flush error %v, want it to contain %q", err, want)
+	}
+	if err := w.Flush(); err != nil {
+		t.Errorf("second Flush error %v, want none", err)
+	}
+}
@@ -0,0 +1,127 @@
+package flogger
+
+import (
+	"github.com/sirupsen/logrus"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// maximumCallerDepth restricts how many frames are inspected when resolving the caller.
+const maximumCallerDepth = 32
+
+// logrusPackage is the import path of logrus, whose frames sit between flogger and the formatter.
+const logrusPackage = "github.com/sirupsen/logrus"
+
+// slogPackage is the import path of log/slog, whose frames sit between the user and flogger's slog handler.
+const slogPackage = "log/slog"
+
+// stdlogPackage is the import path of the standard log package, whose frames sit between the user and Writer.
+const stdlogPackage = "log"
+
+// runtimePackage is the import path of the runtime, whose frames end every goroutine's stack.
+const runtimePackage = "runtime"
+
+// floggerPackage is the import path of this package, resolved once so the module can be renamed freely.
+var floggerPackage = reflect.TypeOf(Logger{}).PkgPath()
+
+// resolveCaller returns the first stack frame outside of flogger, logrus and the standard log packages, i.e. the user's call site.
+// logrus only skips its own frames, so without this the caller would always point at flogger's wrappers.
+// Frames whose function starts with one of the skip prefixes are passed over too, unless every frame is.
+func resolveCaller(skip []string) *runtime.Frame {
+	pcs := make([]uintptr, maximumCallerDepth)
+	// Skip runtime.Callers and resolveCaller itself.
+	depth := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:depth])
+
+	var first *runtime.Frame
+	for {
+		frame, more := frames.Next()
+		if !isInternalFrame(frame.Function) {
+			if !hasAnyPrefix(frame.Function, skip) {
+				return &frame
+			}
+			// Fall back to the user's call site if no frame is left after the skipped ones.
+			if first == nil {
+				first = &frame
+			}
+		}
+		if !more {
+			return first
+		}
+	}
+}
+
+// hasAnyPrefix reports whether s starts with one of prefixes.
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isInternalFrame reports whether the fully qualified function name belongs to flogger, logrus, log, log/slog
+// or the runtime. Entries logged by flogger's own goroutines therefore keep the frame logrus recorded.
+func isInternalFrame(function string) bool {
+	switch packageName(function) {
+	case floggerPackage, logrusPackage, slogPackage, stdlogPackage, runtimePackage:
+		return true
+	default:
+		return false
+	}
+}
+
+// packageName reduces a fully qualified function name such as
+// "github.com/me/app/svc.(*Server).Handle" to its package path "github.com/me/app/svc".
+func packageName(function string) string {
+	// The package path ends at the first dot after the last slash.
+	lastSlash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[lastSlash+1:], "."); dot >= 0 {
+		return function[:lastSlash+1+dot]
+	}
+	return function
+}
+
+// callerHook rewrites entry.Caller to the user's call site before any other hook or the formatter sees the entry.
+// It is registered first on every Logger, so hooks shipping entries elsewhere (e.g. to Sentry) get the right location too.
+type callerHook struct {
+	// skip holds the function name prefixes set with SetCallerSkipPackages, nil to report the user's call site.
+	skip atomic.Pointer[[]string]
+}
+
+// Levels returns all levels, since caller reporting applies regardless of level.
+func (h *callerHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire replaces the caller logrus recorded (one of flogger's wrappers) with the first frame outside flogger, logrus and the standard log packages.
+func (h *callerHook) Fire(entry *logrus.Entry) error {
+	// Caller reporting is disabled, nothing to correct.
+	if entry.Caller == nil {
+		return nil
+	}
+	var skip []string
+	if prefixes := h.skip.Load(); prefixes != nil {
+		skip = *prefixes
+	}
+	if caller := resolveCaller(skip); caller != nil {
+		entry.Caller = caller
+	}
+	return nil
+}
+
+// SetCallerSkipPackages makes caller reporting pass over the frames whose fully qualified function starts with one
+// of prefixes, e.g. "reflect." or "github.com/me/framework/", and report the first frame after them, which gives
+// useful func and file fields for calls made through generic or reflection-heavy code. If every frame matches, the
+// call site is reported as usual. Calling it without prefixes restores the default.
+func (l *Logger) SetCallerSkipPackages(prefixes ...string) {
+	if len(prefixes) == 0 {
+		l.caller.skip.Store(nil)
+		return
+	}
+	skip := append([]string(nil), prefixes...)
+	l.caller.skip.Store(&skip)
+}
@@ -0,0 +1,109 @@
+package flogger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/seyedali-dev/flogger"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// line returns the line it is called from.
+func line() int {
+	_, _, n, _ := runtime.Caller(1)
+	return n
+}
+
+// newCallerLogger returns a Logger reporting the caller as JSON to the returned buffer.
+// The tests of this file live outside package flogger, whose own frames are never reported as the caller.
+func newCallerLogger(t *testing.T) (*flogger.Logger, *bytes.Buffer) {
+	t.Helper()
+	l := flogger.New()
+	var buf bytes.Buffer
+	l.SetOutput(&buf)
+	if err := l.SetFormatter(flogger.FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	l.SetReportCaller(true)
+	return l, &buf
+}
+
+// callerFields returns the func and file fields of the single JSON entry in buf.
+func callerFields(t *testing.T, buf *bytes.Buffer) (function, file interface{}) {
+	t.Helper()
+	var fields map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &fields); err != nil {
+		t.Fatalf("invalid JSON entry %q: %v", buf.String(), err)
+	}
+	return fields["func"], fields["file"]
+}
+
+func TestCallerIsUserCallSite(t *testing.T) {
+	l, buf := newCallerLogger(t)
+
+	tests := []struct {
+		name string
+		log  func() int
+	}{
+		{"Logger", func() int { l.Info("msg"); return line() }},
+		{"Entry", func() int { l.WithField("k", 1).Warn("msg"); return line() }},
+		{"Infow", func() int { l.Infow("msg", "k", 1); return line() }},
+		{"Notice", func() int { l.Notice("msg"); return line() }},
+		{"Writer", func() int { _, _ = l.Writer("info").Write([]byte("msg\n")); return line() }},
+		{"Std", func() int { l.Std().Print("msg"); return line() }},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		want := fmt.Sprintf("caller_test.go:%d", tt.log())
+
+		function, file := callerFields(t, buf)
+		if file != want {
+			t.Errorf("%s: file = %v, want %s", tt.name, file, want)
+		}
+		if function == nil {
+			t.Errorf("%s: func field missing", tt.name)
+		}
+	}
+}
+
+// logVia logs msg with l, standing in for framework code between the user and the logger.
+func logVia(l *flogger.Logger, msg string) int {
+	l.Info(msg)
+	return line() - 1
+}
+
+func TestCallerSkipPackages(t *testing.T) {
+	l, buf := newCallerLogger(t)
+	// Call logVia through reflection, so the stack holds reflect frames between the call site and logVia.
+	via := reflect.ValueOf(logVia)
+	call := func() (site, logged int) {
+		logged = int(via.Call([]reflect.Value{reflect.ValueOf(l), reflect.ValueOf("msg")})[0].Int())
+		return line() - 1, logged
+	}
+
+	tests := []struct {
+		name     string
+		prefixes []string
+		wantSite bool
+	}{
+		{"default", nil, false},
+		{"skipped", []string{"github.com/seyedali-dev/flogger_test.logVia", "reflect."}, true},
+		// Every frame matches, so the call site is reported as usual.
+		{"fallback", []string{"github.com/seyedali-dev/flogger_test.", "reflect.", "testing."}, false},
+	}
+	for _, tt := range tests {
+		l.SetCallerSkipPackages(tt.prefixes...)
+		buf.Reset()
+		site, logged := call()
+
+		want := fmt.Sprintf("caller_test.go:%d", logged)
+		if tt.wantSite {
+			want = fmt.Sprintf("caller_test.go:%d", site)
+		}
+		if _, file := callerFields(t, buf); file != want {
+			t.Errorf("%s: file = %v, want %s", tt.name, file, want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package flogger
+
+import (
+	"bytes"
+	"io"
+)
+
+// CaptureOutput runs fn with this logger's output redirected to an in-memory buffer and returns what was logged.
+// The previous outputs (including SplitOutput) are restored afterwards, even if fn panics.
+// Colors are only included if they were forced with SetColors(true).
+func (l *Logger) CaptureOutput(fn func()) []byte {
+	var buf bytes.Buffer
+
+	l.mu.Lock()
+	outputs := l.outputs
+	levelWriters := l.levelOutputs.swap(nil)
+	l.outputs = []io.Writer{&buf}
+	l.applyOutputs()
+	l.mu.Unlock()
+
+	restore := func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.outputs = outputs
+		l.levelOutputs.set(levelWriters)
+		// This also drains the asynchronous writer into buf, so it is complete once restore returns.
+		l.applyOutputs()
+	}
+	// Restore the previous outputs even if fn panics.
+	restored := false
+	defer func() {
+		if !restored {
+			restore()
+		}
+	}()
+
+	fn()
+	restore()
+	restored = true
+	return buf.Bytes()
+}
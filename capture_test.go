@@ -0,0 +1,53 @@
+package flogger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCaptureOutput(t *testing.T) {
+	l, buf := newTestLogger()
+
+	out := l.CaptureOutput(func() { l.Info("captured") })
+	l.Info("after")
+
+	if !strings.Contains(string(out), "captured") {
+		t.Errorf("capture %q lacks the entry", out)
+	}
+	if strings.Contains(buf.String(), "captured") || !strings.Contains(buf.String(), "after") {
+		t.Errorf("output %q, want only the entry logged after the capture", buf.String())
+	}
+}
+
+func TestCaptureOutputAsync(t *testing.T) {
+	l, buf := newTestLogger()
+	l.EnableAsync(1024)
+	defer l.Close()
+
+	for i := 0; i < 200; i++ {
+		out := l.CaptureOutput(func() { l.Info("hello") })
+		if !strings.Contains(string(out), "hello") {
+			t.Fatalf("capture %d = %q, want the entry", i, out)
+		}
+	}
+	if err := l.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("output %q, want nothing outside the captures", buf.String())
+	}
+}
+
+func TestCaptureOutputRestoresAfterPanic(t *testing.T) {
+	l, buf := newTestLogger()
+
+	func() {
+		defer func() { _ = recover() }()
+		l.CaptureOutput(func() { panic("boom") })
+	}()
+	l.Info("after")
+
+	if !strings.Contains(buf.String(), "after") {
+		t.Errorf("output %q, want the entry logged after the panic", buf.String())
+	}
+}
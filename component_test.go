@@ -0,0 +1,49 @@
+package flogger
+
+import (
+	"github.com/sirupsen/logrus"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestComponentLevel(t *testing.T) {
+	l, buf := newTestLogger()
+	if err := l.SetComponentLevel("db", "debug"); err != nil {
+		t.Fatal(err)
+	}
+
+	l.WithComponent("db").Debug("query")
+	l.Debug("hidden")
+
+	if !strings.Contains(buf.String(), "query") || strings.Contains(buf.String(), "hidden") {
+		t.Errorf("output %q, want only the component's Debug entry", buf.String())
+	}
+}
+
+func TestConcurrentComponentLevels(t *testing.T) {
+	l, _ := newTestLogger()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = l.SetComponentLevel("db", "debug")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = l.SetLevel("warn")
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The logrus level must stay verbose enough for the db component whichever call ran last.
+	if got := l.log.GetLevel(); got != logrus.DebugLevel {
+		t.Errorf("logrus level = %s, want debug", got)
+	}
+}
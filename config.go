@@ -0,0 +1,162 @@
+package flogger
+
+import (
+	"github.com/sirupsen/logrus"
+	"io"
+)
+
+// Config describes a complete logger configuration, e.g. parsed from an application's YAML file.
+// Zero values leave the corresponding setting unchanged.
+type Config struct {
+	// Level is the minimum level to log, e.g. "debug" (see SetLevel).
+	Level string
+	// Format is the output format, one of the Format* constants (see SetFormatter).
+	Format string
+	// Colors forces colored text output on or off when set (see SetColors).
+	Colors *bool
+	// ReportCaller enables or disables the func and file fields when set (see SetReportCaller).
+	ReportCaller *bool
+	// TimestampFormat is the layout used to render timestamps (see SetTimestampFormat).
+	TimestampFormat string
+	// Output is the writer entries are written to (see SetOutput).
+	Output io.Writer
+	// Fields replaces the default fields added to every entry when set (see SetDefaultFields).
+	Fields map[string]interface{}
+
+	// snapshot holds the state captured by Snapshot that the fields above cannot express, restored by Restore.
+	snapshot *snapshot
+}
+
+// snapshot is the part of a Logger's configuration captured by Snapshot beyond the exported Config fields.
+type snapshot struct {
+	// opts are the formatter options.
+	opts formatterOptions
+	// outputs are the configured writers.
+	outputs []io.Writer
+	// levelOutputs is the level routing of SplitOutput and SetSyslogOutput, nil if there is none.
+	levelOutputs map[logrus.Level]io.Writer
+}
+
+// Configure applies every set field of cfg to this logger in one step.
+// It returns an error without changing anything if Level or Format is invalid.
+func (l *Logger) Configure(cfg Config) error {
+	// Validate everything up front so an invalid config leaves the logger untouched.
+	level, err := cfg.validate()
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.configure(cfg, level)
+	return nil
+}
+
+// Snapshot returns the current configuration: the level, the formatter settings, the outputs and the default fields.
+// Pass it to Restore to put that configuration back, e.g. around code or tests that reconfigure the logger:
+//
+//	defer logger.Restore(logger.Snapshot())
+//
+// Component levels, hooks and the async, sampling and deduplication settings are not part of the snapshot.
+func (l *Logger) Snapshot() Config {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	reportCaller := l.log.ReportCaller
+	cfg := Config{
+		Level:           l.levels.getGlobal().String(),
+		Format:          l.opts.format,
+		ReportCaller:    &reportCaller,
+		TimestampFormat: l.opts.timestampFormat,
+		Fields:          l.defaults.get(),
+		snapshot: &snapshot{
+			opts:         l.opts,
+			outputs:      append([]io.Writer(nil), l.outputs...),
+			levelOutputs: l.levelOutputs.get(),
+		},
+	}
+	// Automatic colors have no Config equivalent, Restore brings them back from the snapshot.
+	if l.opts.colors != colorsAuto {
+		colors := l.opts.colors == colorsOn
+		cfg.Colors = &colors
+	}
+	// Several outputs or a level routing cannot be expressed as a single Output either.
+	if len(l.outputs) == 1 && cfg.snapshot.levelOutputs == nil {
+		cfg.Output = l.outputs[0]
+	}
+	return cfg
+}
+
+// Restore puts back the configuration captured by Snapshot. Fields of cfg changed since then are applied on top,
+// like Configure does, and a Config built by hand is simply applied like Configure.
+// It returns an error without changing anything if Level or Format is invalid.
+func (l *Logger) Restore(cfg Config) error {
+	level, err := cfg.validate()
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if s := cfg.snapshot; s != nil {
+		l.opts = s.opts
+		l.outputs = append([]io.Writer(nil), s.outputs...)
+		l.levelOutputs.set(s.levelOutputs)
+	}
+	l.configure(cfg, level)
+	return nil
+}
+
+// validate checks the Level and Format of cfg and returns the parsed level.
+func (cfg Config) validate() (logrus.Level, error) {
+	var level logrus.Level
+	if cfg.Level != "" {
+		lvl, err := logrus.ParseLevel(cfg.Level)
+		if err != nil {
+			return 0, err
+		}
+		level = lvl
+	}
+	if cfg.Format != "" {
+		if err := validateFormat(cfg.Format); err != nil {
+			return 0, err
+		}
+	}
+	return level, nil
+}
+
+// configure applies every set field of the validated cfg, with level parsed from cfg.Level. The caller must hold l.mu.
+func (l *Logger) configure(cfg Config, level logrus.Level) {
+	if cfg.Level != "" {
+		l.setLevel(level)
+	}
+	if cfg.ReportCaller != nil {
+		l.log.SetReportCaller(*cfg.ReportCaller)
+	}
+	if cfg.Format != "" {
+		l.opts.format = cfg.Format
+	}
+	if cfg.Colors != nil {
+		if *cfg.Colors {
+			l.opts.colors = colorsOn
+		} else {
+			l.opts.colors = colorsOff
+		}
+	}
+	if cfg.TimestampFormat != "" {
+		l.opts.timestampFormat = cfg.TimestampFormat
+		l.opts.disableTimestamp = false
+	}
+	if cfg.Output != nil {
+		l.levelOutputs.set(nil)
+		l.outputs = []io.Writer{cfg.Output}
+	}
+	if cfg.Fields != nil {
+		l.defaults.set(cfg.Fields)
+	}
+
+	// Install the outputs and a formatter built from the updated options.
+	l.applyOutputs()
+}
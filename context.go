@@ -0,0 +1,116 @@
+package flogger
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"sync"
+)
+
+// contextKey is the type of the context keys used by flogger, unexported to avoid collisions with other packages.
+type contextKey int
+
+const (
+	// traceIDKey is the context key under which ContextWithTraceID stores the trace ID.
+	traceIDKey contextKey = iota
+	// loggerKey is the context key under which IntoContext stores the logger.
+	loggerKey
+	// outputsKey is the context key under which WithOutput stores the additional writers of an entry.
+	outputsKey
+)
+
+// TraceIDField is the field under which the trace ID carried by a context is logged.
+const TraceIDField = "trace_id"
+
+// ContextErrorField is the field under which the error of a canceled or expired context is logged,
+// e.g. "context deadline exceeded".
+const ContextErrorField = "ctx_err"
+
+// ContextWithTraceID returns a copy of ctx carrying the given trace ID.
+// Entries logged with that context (see WithContext and InfoContext) include it as the trace_id field.
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey, traceID)
+}
+
+// TraceIDFromContext returns the trace ID stored in ctx by ContextWithTraceID, if any.
+func TraceIDFromContext(ctx context.Context) (string, bool) {
+	traceID, ok := ctx.Value(traceIDKey).(string)
+	return traceID, ok && traceID != ""
+}
+
+// IntoContext returns a copy of ctx carrying l, so functions deeper in the call stack can log through it,
+// e.g. with the request fields of a Child logger, after retrieving it with FromContext.
+func IntoContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, l)
+}
+
+// FromContext returns the logger stored in ctx by IntoContext, or the package-level logger if there is none.
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(loggerKey).(*Logger); ok && l != nil {
+		return l
+	}
+	return std()
+}
+
+// contextHook adds correlation data carried by the entry's context as fields.
+type contextHook struct {
+	// mu guards extractors.
+	mu sync.RWMutex
+	// extractors holds the functions registered with AddContextExtractor in registration order.
+	extractors []func(ctx context.Context) map[string]interface{}
+}
+
+// Levels returns all levels, since correlation data applies regardless of level.
+func (h *contextHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds the trace ID of the entry's context, the fields of the context extractors and, if the context is done,
+// its error, unless the entry already sets the respective field.
+func (h *contextHook) Fire(entry *logrus.Entry) error {
+	// The entry was not logged with a context.
+	if entry.Context == nil {
+		return nil
+	}
+
+	if traceID, ok := TraceIDFromContext(entry.Context); ok {
+		if _, exists := entry.Data[TraceIDField]; !exists {
+			entry.Data[TraceIDField] = traceID
+		}
+	}
+
+	h.mu.RLock()
+	extractors := h.extractors
+	h.mu.RUnlock()
+	for _, extract := range extractors {
+		for k, v := range extract(entry.Context) {
+			if _, exists := entry.Data[k]; !exists {
+				entry.Data[k] = v
+			}
+		}
+	}
+
+	// A healthy context has no error, so the field only shows up once it was canceled or its deadline passed.
+	if err := entry.Context.Err(); err != nil {
+		if _, exists := entry.Data[ContextErrorField]; !exists {
+			entry.Data[ContextErrorField] = err
+		}
+	}
+	return nil
+}
+
+// add registers an extractor after the existing ones.
+func (h *contextHook) add(extract func(ctx context.Context) map[string]interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Copy the slice, Fire may still be iterating the previous one.
+	h.extractors = append(h.extractors[:len(h.extractors):len(h.extractors)], extract)
+}
+
+// AddContextExtractor registers fn to pull correlation fields out of the context of entries logged with one, such as
+// those of InfoContext or WithContext, e.g. the IDs of a tracing span. Extractors run in registration order while
+// the entry is enriched, so every output including SplitOutput and WithOutput writers sees their fields, and they
+// never override fields already set on the entry. fn must be safe for concurrent use.
+func (l *Logger) AddContextExtractor(fn func(ctx context.Context) map[string]interface{}) {
+	l.context.add(fn)
+}
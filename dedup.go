@@ -0,0 +1,118 @@
+package flogger
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// deduper collapses identical consecutive entries into the first one and a "last message repeated N times" summary.
+// Entries are compared on their level, message and fields, so differing timestamps do not defeat the comparison.
+type deduper struct {
+	// logger receives the summaries of collapsed entries.
+	logger *Logger
+	// window is how long repeats of the last entry are collapsed before their count is reported.
+	window time.Duration
+
+	// mu guards the fields below.
+	mu sync.Mutex
+	// last identifies the last entry let through, empty if there is none to compare with.
+	last string
+	// level is the level of the last entry, used for its summary.
+	level logrus.Level
+	// msg is the message of the last entry, used for its summary.
+	msg string
+	// repeated is the number of repeats of the last entry collapsed so far.
+	repeated int
+	// timer reports the repeats once the window elapses, nil while there are none.
+	timer *time.Timer
+}
+
+// newDeduper creates a deduper for the logger.
+func newDeduper(logger *Logger, window time.Duration) *deduper {
+	return &deduper{logger: logger, window: window}
+}
+
+// allow reports whether the entry differs from the last one, reporting the repeats of the last one first if it does.
+func (d *deduper) allow(data logrus.Fields, level logrus.Level, msg string) bool {
+	key := dedupKey(data, level, msg)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if key == d.last {
+		d.repeated++
+		if d.timer == nil {
+			d.timer = time.AfterFunc(d.window, d.expire)
+		}
+		return false
+	}
+
+	d.flush()
+	d.last, d.level, d.msg = key, level, msg
+	return true
+}
+
+// expire reports the repeats once the window has elapsed. A further repeat is logged again as a new entry.
+func (d *deduper) expire() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.flush()
+	d.last = ""
+}
+
+// flush logs the summary of the collapsed repeats, if any. The caller must hold d.mu.
+func (d *deduper) flush() {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	if d.repeated > 0 {
+		// Log through logrus directly, the summary itself must not be collapsed.
+		d.logger.log.WithField("message", d.msg).Logf(d.level, "last message repeated %d times", d.repeated)
+		d.repeated = 0
+	}
+}
+
+// close reports the repeats still pending.
+func (d *deduper) close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.flush()
+	d.last = ""
+}
+
+// dedupKey identifies an entry by its level, message and fields, sorted by key.
+func dedupKey(data logrus.Fields, level logrus.Level, msg string) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d\x00%s", level, msg)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "\x00%s=%+v", key, data[key])
+	}
+	return b.String()
+}
+
+// SetDedup collapses identical consecutive entries: repeats of the last entry within window are dropped,
+// and a "last message repeated N times" summary is logged when a different entry arrives, the window elapses
+// or the logger is closed. Entries are compared on level, message and fields, not on their timestamps.
+// Fatal and Panic entries are never collapsed. A window of zero or less turns deduplication off again.
+func (l *Logger) SetDedup(window time.Duration) {
+	var d *deduper
+	if window > 0 {
+		d = newDeduper(l, window)
+	}
+	if previous := l.dedup.Swap(d); previous != nil {
+		previous.close()
+	}
+}
@@ -0,0 +1,77 @@
+package flogger
+
+import (
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+// levelElevation tracks a temporary global level set by SetLevelFor and the timer restoring the previous one.
+type levelElevation struct {
+	// mu guards every field.
+	mu sync.Mutex
+	// timer restores the previous level once it fires, nil while no temporary level is set.
+	timer *time.Timer
+	// previous is the global level to restore.
+	previous logrus.Level
+	// generation identifies the current timer, so a timer stopped too late to prevent it from firing is ignored.
+	generation uint64
+}
+
+// SetLevelFor sets the global level for the duration d and then restores the level that was set before, e.g. to
+// capture a burst of debug entries in production. Calling it again while a temporary level is set extends the
+// temporary period with the new level and still restores the original level. CancelLevelFor restores it right away,
+// and SetLevel replaces it for good. It returns an error for unknown levels, leaving the current level untouched.
+func (l *Logger) SetLevelFor(level string, d time.Duration) error {
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	e := &l.elevation
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// Keep the level from before the first of consecutive calls, and replace their timer instead of adding one.
+	if e.timer != nil {
+		e.timer.Stop()
+	} else {
+		e.previous = l.levels.getGlobal()
+	}
+	e.generation++
+	generation := e.generation
+	e.timer = time.AfterFunc(d, func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		if e.generation == generation {
+			e.timer = nil
+			l.levels.setGlobal(l.log, e.previous)
+		}
+	})
+
+	l.levels.setGlobal(l.log, lvl)
+	return nil
+}
+
+// CancelLevelFor restores the level set before SetLevelFor right away. It is a no-op without a temporary level.
+func (l *Logger) CancelLevelFor() {
+	e := &l.elevation
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.timer == nil {
+		return
+	}
+	e.stop()
+	l.levels.setGlobal(l.log, e.previous)
+}
+
+// stop discards the temporary level's timer without restoring the previous level. The caller must hold e.mu.
+func (e *levelElevation) stop() {
+	if e.timer != nil {
+		e.timer.Stop()
+		e.timer = nil
+		e.generation++
+	}
+}
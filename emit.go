@@ -0,0 +1,156 @@
+package flogger
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// logf formats a message and logs it at the given level. It is the path every formatted leveled method
+// of Logger and Entry goes through; a nil entry logs without fields.
+func (l *Logger) logf(entry *logrus.Entry, level logrus.Level, format string, args []interface{}) {
+	// Skip formatting entirely for disabled levels. A Panic entry still needs its message to panic with.
+	if !l.enabled(level) && level != logrus.PanicLevel {
+		l.exitIfFatal(level)
+		return
+	}
+	// Without arguments the format is the message itself, so a literal "%" is not mistaken for a verb.
+	msg := format
+	if len(args) > 0 {
+		msg = fmt.Sprintf(format, args...)
+	}
+	l.writeKeyed(entry, level, msg, format)
+}
+
+// logln logs its operands at the given level without formatting, separated by spaces like fmt.Sprintln.
+func (l *Logger) logln(entry *logrus.Entry, level logrus.Level, args []interface{}) {
+	if !l.enabled(level) && level != logrus.PanicLevel {
+		l.exitIfFatal(level)
+		return
+	}
+	l.write(entry, level, sprintlnn(args))
+}
+
+// logw logs msg at the given level with alternating keys and values as fields.
+func (l *Logger) logw(entry *logrus.Entry, level logrus.Level, msg string, keysAndValues []interface{}) {
+	// Skip building the fields entirely for disabled levels.
+	if !l.enabled(level) && level != logrus.PanicLevel {
+		l.exitIfFatal(level)
+		return
+	}
+	if entry == nil {
+		entry = l.newEntry()
+	}
+	l.write(entry.WithFields(keysAndValuesToFields(keysAndValues)), level, msg)
+}
+
+// logFunc logs the message returned by fn at the given level, calling fn only if the level is enabled.
+func (l *Logger) logFunc(entry *logrus.Entry, level logrus.Level, fn func() string) {
+	// Skip building the message entirely for disabled levels, which is the point of the lazy API.
+	// Component levels are checked here too, as the logrus level may be more verbose because of them.
+	// Fatal and Panic entries are left to write, which exits or panics even if they are dropped.
+	data := l.fields
+	if entry != nil {
+		data = entry.Data
+	}
+	if level > logrus.FatalLevel && (!l.enabled(level) || !l.levels.allow(data, level)) {
+		return
+	}
+	l.write(entry, level, fn())
+}
+
+// write emits msg at the given level unless it is below its component's level or a filter such as the sampler drops it.
+// logrus hooks cannot veto an entry, so filtering happens here, before anything is formatted.
+// Like logrus' own Fatal and Panic methods, the Fatal level exits (see SetExitCode) and the Panic level panics after logging.
+func (l *Logger) write(entry *logrus.Entry, level logrus.Level, msg string) {
+	l.writeKeyed(entry, level, msg, msg)
+}
+
+// writeKeyed is write for messages built from a format string, which SetSamplerTick counts them by as key.
+func (l *Logger) writeKeyed(entry *logrus.Entry, level logrus.Level, msg, key string) {
+	if l.disabled.Load() {
+		l.dropped(entry, level, msg)
+		return
+	}
+
+	// A child logger's entries always carry its fields.
+	if entry == nil && l.fields != nil {
+		entry = l.newEntry()
+	}
+
+	// The logrus level may be more verbose than configured because of component levels, so check the entry's own.
+	var data logrus.Fields
+	if entry != nil {
+		data = entry.Data
+	}
+	if !l.levels.allow(data, level) {
+		l.dropped(entry, level, msg)
+		return
+	}
+
+	if ls := l.levelSampler.Load(); ls != nil && !ls.allow(level) {
+		return
+	}
+
+	// Fatal and Panic entries have side effects and are never dropped.
+	if t := l.tickSampler.Load(); t != nil && level > logrus.FatalLevel && !t.allow(key) {
+		return
+	}
+
+	// Fatal and Panic entries have side effects and are never dropped.
+	if s := l.sampler.Load(); s != nil && level > logrus.FatalLevel && !s.allow(level, msg) {
+		return
+	}
+
+	// Fatal and Panic entries have side effects and are never collapsed.
+	if d := l.dedup.Load(); d != nil && level > logrus.FatalLevel && !d.allow(data, level, msg) {
+		return
+	}
+
+	// A Panic entry unwinds out of Log, flush the outputs on the way so it is not lost if the process crashes.
+	if level == logrus.PanicLevel {
+		defer l.Flush()
+	}
+
+	if entry == nil {
+		l.log.Log(level, msg)
+	} else {
+		entry.Log(level, msg)
+	}
+	l.exitIfFatal(level)
+}
+
+// enabled reports whether entries at level can be logged at all, i.e. logging is not disabled and level is not filtered out.
+func (l *Logger) enabled(level logrus.Level) bool {
+	return !l.disabled.Load() && l.log.IsLevelEnabled(level)
+}
+
+// dropped gives an entry that is not logged the side effect of its level: a Fatal entry still exits and a Panic entry
+// still panics, with the entry like logrus does, so code after Fatal and Panic calls never runs.
+func (l *Logger) dropped(entry *logrus.Entry, level logrus.Level, msg string) {
+	if level == logrus.PanicLevel {
+		if entry == nil {
+			entry = l.newEntry()
+		}
+		panicked := entry.Dup()
+		panicked.Time = time.Now()
+		panicked.Level = level
+		panicked.Message = msg
+		panic(panicked)
+	}
+	l.exitIfFatal(level)
+}
+
+// exitIfFatal runs the exit handlers and terminates the process with the configured exit code if level is the Fatal level.
+func (l *Logger) exitIfFatal(level logrus.Level) {
+	if level == logrus.FatalLevel {
+		l.log.Exit(int(l.exitCode.Load()))
+	}
+}
+
+// sprintlnn formats its operands like fmt.Sprintln without the trailing newline,
+// which always separates operands by spaces unlike fmt.Sprint.
+func sprintlnn(args []interface{}) string {
+	msg := fmt.Sprintln(args...)
+	return msg[:len(msg)-1]
+}
@@ -0,0 +1,32 @@
+package flogger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLiteralPercentWithoutArgs(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		log  func(l *Logger)
+		want string
+	}{
+		{"Logger", func(l *Logger) { l.Info("plain message with 50% off") }, "plain message with 50% off"},
+		{"verb", func(l *Logger) { l.Warn("50% done %d") }, "50% done %d"},
+		{"Entry", func(l *Logger) { l.WithField("k", 1).Info("100%") }, "100%"},
+		{"formatted", func(l *Logger) { l.Info("%d%%", 5) }, "5%"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger()
+			tt.log(l)
+
+			out := buf.String()
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("output %q does not contain %q", out, tt.want)
+			}
+			if strings.Contains(out, "%!") {
+				t.Errorf("output %q has a formatting error", out)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package flogger
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"io"
+)
+
+// Entry is a log entry that carries structured fields, created by WithFields.
+// An Entry is immutable once built, so it is safe to reuse it across multiple log calls.
+type Entry struct {
+	// logger is the Logger the entry was created from and is emitted through.
+	logger *Logger
+	// entry is the underlying logrus entry holding the fields.
+	entry *logrus.Entry
+	// group is prepended to the keys of the fields added to the entry, e.g. "db.", empty outside of any group.
+	group string
+}
+
+// WithFields returns a new Entry carrying the fields of this entry plus the given fields.
+// Keys in fields override keys already present on the entry.
+func (e *Entry) WithFields(fields map[string]interface{}) *Entry {
+	return &Entry{logger: e.logger, entry: e.entry.WithFields(e.grouped(fields)), group: e.group}
+}
+
+// WithField returns a new Entry carrying the fields of this entry plus the given key-value pair.
+func (e *Entry) WithField(key string, value interface{}) *Entry {
+	return &Entry{logger: e.logger, entry: e.entry.WithField(e.group+key, value), group: e.group}
+}
+
+// WithError returns a new Entry carrying the fields of this entry plus err, see Logger.WithError.
+func (e *Entry) WithError(err error) *Entry {
+	return &Entry{logger: e.logger, entry: e.entry.WithFields(e.grouped(errorFields(err))), group: e.group}
+}
+
+// WithContext returns a new Entry carrying the fields of this entry, bound to ctx.
+func (e *Entry) WithContext(ctx context.Context) *Entry {
+	// Keep the writers added with WithOutput, they travel in the context.
+	if writers := entryOutputs(e.entry.Context); writers != nil {
+		ctx = withEntryOutputs(ctx, writers)
+	}
+	return &Entry{logger: e.logger, entry: e.entry.WithContext(ctx), group: e.group}
+}
+
+// WithPrefix returns a new Entry carrying the fields of this entry whose messages are tagged with prefix,
+// replacing any previous prefix, see Logger.WithPrefix.
+func (e *Entry) WithPrefix(prefix string) *Entry {
+	return &Entry{logger: e.logger, entry: e.entry.WithField(prefixField, prefix), group: e.group}
+}
+
+// WithOutput returns a new Entry carrying the fields of this entry whose entries are also written to w,
+// in addition to the logger's outputs and the writers added before, see Logger.WithOutput.
+func (e *Entry) WithOutput(w io.Writer) *Entry {
+	writers := entryOutputs(e.entry.Context)
+	// Copy the writers, entries built from this one before must keep theirs.
+	writers = append(writers[:len(writers):len(writers)], w)
+	return &Entry{logger: e.logger, entry: e.entry.WithContext(withEntryOutputs(e.entry.Context, writers)), group: e.group}
+}
+
+// WithGroup returns a new Entry carrying the fields of this entry whose further fields are namespaced under name,
+// e.g. WithGroup("db").WithField("rows", 3) adds the db.rows field. Nested groups are joined with dots like slog's,
+// and an empty name returns the entry unchanged.
+func (e *Entry) WithGroup(name string) *Entry {
+	if name == "" {
+		return e
+	}
+	return &Entry{logger: e.logger, entry: e.entry, group: e.group + name + "."}
+}
+
+// grouped returns fields with their keys prefixed by the entry's group.
+func (e *Entry) grouped(fields logrus.Fields) logrus.Fields {
+	if e.group == "" {
+		return fields
+	}
+	grouped := make(logrus.Fields, len(fields))
+	for k, v := range fields {
+		grouped[e.group+k] = v
+	}
+	return grouped
+}
+
+// groupedKeysAndValues returns alternating keys and values with the keys prefixed by the entry's group.
+func (e *Entry) groupedKeysAndValues(keysAndValues []interface{}) []interface{} {
+	if e.group == "" {
+		return keysAndValues
+	}
+	grouped := make([]interface{}, len(keysAndValues))
+	copy(grouped, keysAndValues)
+	for i := 0; i < len(grouped); i += 2 {
+		grouped[i] = e.group + fmt.Sprint(grouped[i])
+	}
+	return grouped
+}
+
+// log level methods
+
+// Info logs a message at the Info level with formatting, including the entry's fields.
+func (e *Entry) Info(format string, args ...interface{}) {
+	e.logger.logf(e.entry, logrus.InfoLevel, format, args)
+}
+
+// Warn logs a message at the Warn level with formatting, including the entry's fields.
+func (e *Entry) Warn(format string, args ...interface{}) {
+	e.logger.logf(e.entry, logrus.WarnLevel, format, args)
+}
+
+// Error logs a message at the Error level with formatting, including the entry's fields.
+func (e *Entry) Error(format string, args ...interface{}) {
+	e.logger.logf(e.entry, logrus.ErrorLevel, format, args)
+}
+
+// Debug logs a message at the Debug level with formatting, including the entry's fields.
+func (e *Entry) Debug(format string, args ...interface{}) {
+	e.logger.logf(e.entry, logrus.DebugLevel, format, args)
+}
+
+// Trace logs a message at the Trace level with formatting, including the entry's fields.
+func (e *Entry) Trace(format string, args ...interface{}) {
+	e.logger.logf(e.entry, logrus.TraceLevel, format, args)
+}
+
+// Fatal logs a message at the Fatal level with formatting, including the entry's fields,
+// and then terminates the process with os.Exit(1).
+func (e *Entry) Fatal(format string, args ...interface{}) {
+	e.logger.logf(e.entry, logrus.FatalLevel, format, args)
+}
+
+// Panic logs a message at the Panic level with formatting, including the entry's fields,
+// and then panics with the logged entry.
+func (e *Entry) Panic(format string, args ...interface{}) {
+	e.logger.logf(e.entry, logrus.PanicLevel, format, args)
+}
+
+// Log logs a message with formatting at the level named level, chosen at runtime, including the entry's fields,
+// see Logger.Log.
+func (e *Entry) Log(level, format string, args ...interface{}) {
+	e.logger.logNamedLevel(e.entry, level, format, args)
+}
+
+// LogAt logs a message with formatting at the given level, chosen at runtime, including the entry's fields.
+func (e *Entry) LogAt(level logrus.Level, format string, args ...interface{}) {
+	e.logger.logf(e.entry, level, format, args)
+}
+
+// Infoln logs its operands at the Info level without formatting, including the entry's fields.
+func (e *Entry) Infoln(args ...interface{}) {
+	e.logger.logln(e.entry, logrus.InfoLevel, args)
+}
+
+// Warnln logs its operands at the Warn level without formatting, including the entry's fields.
+func (e *Entry) Warnln(args ...interface{}) {
+	e.logger.logln(e.entry, logrus.WarnLevel, args)
+}
+
+// Errorln logs its operands at the Error level without formatting, including the entry's fields.
+func (e *Entry) Errorln(args ...interface{}) {
+	e.logger.logln(e.entry, logrus.ErrorLevel, args)
+}
+
+// Infow logs a message at the Info level with the entry's fields plus the given alternating keys and values.
+func (e *Entry) Infow(msg string, keysAndValues ...interface{}) {
+	e.logger.logw(e.entry, logrus.InfoLevel, msg, e.groupedKeysAndValues(keysAndValues))
+}
+
+// Warnw logs a message at the Warn level with the entry's fields plus the given alternating keys and values.
+func (e *Entry) Warnw(msg string, keysAndValues ...interface{}) {
+	e.logger.logw(e.entry, logrus.WarnLevel, msg, e.groupedKeysAndValues(keysAndValues))
+}
+
+// Errorw logs a message at the Error level with the entry's fields plus the given alternating keys and values.
+func (e *Entry) Errorw(msg string, keysAndValues ...interface{}) {
+	e.logger.logw(e.entry, logrus.ErrorLevel, msg, e.groupedKeysAndValues(keysAndValues))
+}
+
+// InfoFunc logs the message returned by fn at the Info level, including the entry's fields, calling fn only if the level is enabled.
+func (e *Entry) InfoFunc(fn func() string) {
+	e.logger.logFunc(e.entry, logrus.InfoLevel, fn)
+}
+
+// WarnFunc logs the message returned by fn at the Warn level, including the entry's fields, calling fn only if the level is enabled.
+func (e *Entry) WarnFunc(fn func() string) {
+	e.logger.logFunc(e.entry, logrus.WarnLevel, fn)
+}
+
+// ErrorFunc logs the message returned by fn at the Error level, including the entry's fields, calling fn only if the level is enabled.
+func (e *Entry) ErrorFunc(fn func() string) {
+	e.logger.logFunc(e.entry, logrus.ErrorLevel, fn)
+}
+
+// DebugFunc logs the message returned by fn at the Debug level, including the entry's fields, calling fn only if the level is enabled.
+func (e *Entry) DebugFunc(fn func() string) {
+	e.logger.logFunc(e.entry, logrus.DebugLevel, fn)
+}
@@ -0,0 +1,72 @@
+package flogger
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+// newTestLogger returns a Logger writing uncolored text to the returned buffer.
+func newTestLogger() (*Logger, *bytes.Buffer) {
+	l := New()
+	var buf bytes.Buffer
+	l.SetOutput(&buf)
+	l.SetColors(false)
+	return l, &buf
+}
+
+func TestWithOutputWritesToSideWriter(t *testing.T) {
+	l, main := newTestLogger()
+	var side bytes.Buffer
+
+	l.WithOutput(&side).WithField("k", 1).Info("status")
+	l.Info("plain")
+
+	if !strings.Contains(side.String(), "status k=1") {
+		t.Errorf("side writer got %q, want the status entry", side.String())
+	}
+	if strings.Contains(side.String(), "plain") {
+		t.Errorf("side writer got %q, want only the status entry", side.String())
+	}
+	if strings.Count(main.String(), "\n") != 2 {
+		t.Errorf("main output got %q, want both entries", main.String())
+	}
+	if strings.Contains(main.String(), "flogger.") {
+		t.Errorf("main output %q renders an internal field", main.String())
+	}
+}
+
+func TestWithOutputSurvivesTruncationAndRedaction(t *testing.T) {
+	l, main := newTestLogger()
+	l.SetMaxFieldLength(5)
+	l.RedactKeys("flogger.output", "secret")
+	var side bytes.Buffer
+
+	l.WithOutput(&side).WithField("secret", "hunter2").Info("status")
+
+	for name, out := range map[string]string{"main": main.String(), "side": side.String()} {
+		if !strings.Contains(out, "status secret=***") {
+			t.Errorf("%s output %q, want the redacted entry", name, out)
+		}
+		if strings.Contains(out, "flogger.") || strings.Contains(out, "truncated") {
+			t.Errorf("%s output %q renders the side writers as a field", name, out)
+		}
+	}
+}
+
+func TestWithOutputKeptByWithContext(t *testing.T) {
+	l, _ := newTestLogger()
+	var first, second bytes.Buffer
+
+	entry := l.WithOutput(&first)
+	entry.WithContext(context.Background()).WithOutput(&second).Info("both")
+	entry.Info("first only")
+
+	if got := first.String(); !strings.Contains(got, "both") || !strings.Contains(got, "first only") {
+		t.Errorf("first writer got %q, want both entries", got)
+	}
+	if got := second.String(); !strings.Contains(got, "both") || strings.Contains(got, "first only") {
+		t.Errorf("second writer got %q, want only the first entry", got)
+	}
+}
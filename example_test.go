@@ -0,0 +1,39 @@
+package flogger_test
+
+import (
+	"fmt"
+	"github.com/seyedali-dev/flogger"
+)
+
+// fakeLogger records the Info messages logged through it.
+type fakeLogger struct {
+	infos []string
+}
+
+func (f *fakeLogger) Debug(format string, args ...interface{}) {}
+
+func (f *fakeLogger) Info(format string, args ...interface{}) {
+	f.infos = append(f.infos, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeLogger) Warn(format string, args ...interface{}) {}
+
+func (f *fakeLogger) Error(format string, args ...interface{}) {}
+
+// greeter depends on flogger.Interface rather than a concrete logger.
+type greeter struct {
+	log flogger.Interface
+}
+
+func (g *greeter) Greet(name string) {
+	g.log.Info("greeting %s", name)
+}
+
+func ExampleInterface() {
+	fake := &fakeLogger{}
+	g := &greeter{log: fake}
+	g.Greet("bob")
+
+	fmt.Println(fake.infos)
+	// Output: [greeting bob]
+}
@@ -0,0 +1,176 @@
+package flogger
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+// ErrorField is the field under which WithError attaches the error.
+const ErrorField = "error"
+
+// ErrorChainField is the field under which WithError attaches the messages of the wrapped errors.
+const ErrorChainField = "error_chain"
+
+// missingValue is the placeholder logged for a dangling key without a value in a key-value list.
+const missingValue = "(MISSING)"
+
+// extractMessageFields moves the key=value tokens at the end of msg into fields and returns the remaining message,
+// e.g. "request done status=200 took=5ms" becomes "request done" with the status and took fields. Only tokens that
+// look unambiguously like a pair are taken, see messageFieldToken, and extraction stops at the first token that
+// does not or whose key is already a field or a standard key. A message made of pairs only is left alone.
+func extractMessageFields(msg string, data logrus.Fields) (string, logrus.Fields) {
+	rest := strings.TrimRight(msg, " ")
+	var extracted logrus.Fields
+	for {
+		i := strings.LastIndexByte(rest, ' ')
+		// The first word always stays, checked below.
+		if i < 0 {
+			break
+		}
+		key, value, ok := messageFieldToken(rest[i+1:])
+		if !ok {
+			break
+		}
+		if _, exists := data[key]; exists || isStandardKey(key) {
+			break
+		}
+		if _, exists := extracted[key]; exists {
+			break
+		}
+		if extracted == nil {
+			extracted = make(logrus.Fields)
+		}
+		extracted[key] = value
+		rest = strings.TrimRight(rest[:i], " ")
+	}
+	if len(extracted) == 0 {
+		return msg, data
+	}
+	if _, _, ok := messageFieldToken(rest); ok {
+		return msg, data
+	}
+
+	// Copy the fields, the entry's own map must not change.
+	fields := make(logrus.Fields, len(data)+len(extracted))
+	for k, v := range data {
+		fields[k] = v
+	}
+	for k, v := range extracted {
+		fields[k] = v
+	}
+	return rest, fields
+}
+
+// isStandardKey reports whether key is one of the keys logrus renders the message, level and time under.
+func isStandardKey(key string) bool {
+	return key == logrus.FieldKeyMsg || key == logrus.FieldKeyLevel || key == logrus.FieldKeyTime
+}
+
+// messageFieldToken splits a key=value token: the key is an identifier of letters, digits, '_', '.' and '-' starting
+// with a letter or '_', and the value is non-empty without quotes or further '=' signs, so URLs with query strings,
+// quoted text and expressions such as "a==b" are not mistaken for pairs.
+func messageFieldToken(token string) (key, value string, ok bool) {
+	key, value, found := strings.Cut(token, "=")
+	if !found || key == "" || value == "" || strings.ContainsAny(value, "=\"'`") {
+		return "", "", false
+	}
+	for i, r := range key {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && (r >= '0' && r <= '9' || r == '.' || r == '-'):
+		default:
+			return "", "", false
+		}
+	}
+	return key, value, true
+}
+
+// keysAndValuesToFields converts alternating keys and values into fields.
+// Non-string keys are converted with fmt.Sprint, and a dangling key is kept with the placeholder value missingValue.
+func keysAndValuesToFields(keysAndValues []interface{}) logrus.Fields {
+	fields := make(logrus.Fields, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+
+		if i+1 < len(keysAndValues) {
+			fields[key] = keysAndValues[i+1]
+		} else {
+			fields[key] = missingValue
+		}
+	}
+	return fields
+}
+
+// errorFields returns the fields describing err: the error itself and, if it wraps other errors,
+// the message of every error in the chain, outermost first.
+func errorFields(err error) logrus.Fields {
+	fields := logrus.Fields{ErrorField: err}
+	if chain := errorChain(err); len(chain) > 1 {
+		fields[ErrorChainField] = chain
+	}
+	return fields
+}
+
+// errorChain returns the messages of err and of every error it wraps, depth first.
+// Both single (Unwrap() error) and multiple (Unwrap() []error) wrapping are followed.
+func errorChain(err error) []string {
+	var chain []string
+	var walk func(err error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+		chain = append(chain, err.Error())
+		switch wrapped := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, inner := range wrapped.Unwrap() {
+				walk(inner)
+			}
+		default:
+			walk(errors.Unwrap(err))
+		}
+	}
+	walk(err)
+	return chain
+}
+
+// Duration is a field value rendered as a human-readable duration such as "1.5s" in text output,
+// and as the raw number of nanoseconds in JSON output, e.g. WithField("elapsed", flogger.Duration(d)).
+type Duration time.Duration
+
+// String renders the duration like time.Duration does, e.g. "1.5s".
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// Bytes is a field value rendered as a human-readable byte size such as "4.2MB" in text output,
+// and as the raw number of bytes in JSON output, e.g. WithField("size", flogger.Bytes(n)).
+type Bytes int64
+
+// byteUnits are the decimal units of Bytes, each 1000 times the previous one.
+var byteUnits = []string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}
+
+// String renders the size with one decimal in the largest decimal unit it reaches, e.g. "4.2MB" or "512B".
+func (b Bytes) String() string {
+	// Work on the magnitude, keeping the sign for negative differences.
+	size, sign := float64(b), ""
+	if size < 0 {
+		size, sign = -size, "-"
+	}
+	if size < 1000 {
+		return fmt.Sprintf("%s%dB", sign, int64(size))
+	}
+
+	unit := 0
+	for size >= 1000 && unit < len(byteUnits)-1 {
+		size /= 1000
+		unit++
+	}
+	return fmt.Sprintf("%s%.1f%s", sign, size, byteUnits[unit])
+}
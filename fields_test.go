@@ -0,0 +1,55 @@
+package flogger
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBytesString(t *testing.T) {
+	tests := map[Bytes]string{
+		0:             "0B",
+		512:           "512B",
+		999:           "999B",
+		1000:          "1.0kB",
+		4_200_000:     "4.2MB",
+		1_500_000_000: "1.5GB",
+		-2048:         "-2.0kB",
+	}
+	for b, want := range tests {
+		if got := b.String(); got != want {
+			t.Errorf("Bytes(%d).String() = %q, want %q", int64(b), got, want)
+		}
+	}
+}
+
+func TestDurationAndBytesText(t *testing.T) {
+	l, buf := newTestLogger()
+
+	l.WithFields(map[string]interface{}{
+		"elapsed": Duration(1500 * time.Millisecond),
+		"size":    Bytes(4_200_000),
+	}).Info("done")
+
+	if !strings.Contains(buf.String(), "done elapsed=1.5s size=4.2MB") {
+		t.Errorf("output %q lacks the human-readable values", buf.String())
+	}
+}
+
+func TestDurationAndBytesJSON(t *testing.T) {
+	l, buf := newJSONLogger(t)
+
+	l.WithFields(map[string]interface{}{
+		"elapsed": Duration(1500 * time.Millisecond),
+		"size":    Bytes(4_200_000),
+	}).Info("done")
+
+	fields := decodeJSON(t, buf.Bytes())
+	// encoding/json decodes numbers as float64.
+	if fields["elapsed"] != float64(1_500_000_000) {
+		t.Errorf("elapsed = %v, want the nanoseconds", fields["elapsed"])
+	}
+	if fields["size"] != float64(4_200_000) {
+		t.Errorf("size = %v, want the bytes", fields["size"])
+	}
+}
@@ -1,86 +1,837 @@
+// Package flogger provides leveled, formatted and structured logging on top of logrus,
+// with a colored prefixed text output by default and caller information on demand.
+//
+// The package-level functions log through a default Logger; use New for independent instances.
+// All functions and Logger methods are safe for concurrent use, including the configuration ones.
 package flogger
 
 import (
-	"fmt"
+	"context"
 	"github.com/sirupsen/logrus"
-	prefixed "github.com/x-cray/logrus-prefixed-formatter"
-	"path"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
 )
 
-// log is a global logger instance that will be used throughout the application.
-var log *logrus.Logger
+// levelEnvVar is the environment variable consulted at startup for the initial log level.
+const levelEnvVar = "FLOGGER_LEVEL"
 
-// customFormatter is a custom log formatter that extends the prefixed.TextFormatter.
-// It adds additional fields like function name and file location to the log output.
-type customFormatter struct {
-	*prefixed.TextFormatter
+// defaultLogger holds the default logger instance used by the package-level functions throughout the application.
+// It is swapped atomically by Use, so goroutines already logging never race with the replacement.
+var defaultLogger atomic.Pointer[Logger]
+
+// std returns the default logger.
+func std() *Logger {
+	return defaultLogger.Load()
 }
 
-// Format is a method that overrides the default Format method of logrus.Entry.
-// It adds custom fields (function name and file location) to the log entry if the caller information is available.
-func (f *customFormatter) Format(entry *logrus.Entry) ([]byte, error) {
-	// Check if the log entry has caller information (file and line number).
-	if entry.HasCaller() {
-		// Extract the function name from the caller.
-		funcVal := entry.Caller.Function
-		// Extract the file name and line number from the caller and format it as "file:line".
-		fileVal := fmt.Sprintf("%s:%d", path.Base(entry.Caller.File), entry.Caller.Line)
+// init is a special function that initializes the default logger when the package is imported.
+func init() {
+	// Create the default logger instance backing the package-level functions.
+	l := New()
+	defaultLogger.Store(l)
 
-		// Initialize the log entry's data fields if they are nil.
-		if entry.Data == nil {
-			entry.Data = make(logrus.Fields)
+	// Override the default Info level if a valid level is provided through the environment.
+	if value := os.Getenv(levelEnvVar); value != "" {
+		if err := l.SetLevel(value); err != nil {
+			l.Warn("ignoring invalid %s value %q, falling back to %s", levelEnvVar, value, l.GetLevel())
 		}
-
-		// Add the function name and file location to the log entry's data.
-		entry.Data["func"] = funcVal
-		entry.Data["file"] = fileVal
 	}
+}
 
-	// Use the parent TextFormatter to format the log entry.
-	return f.TextFormatter.Format(entry)
+// Use replaces the package-level logger with one built on top of an existing logrus logger, keeping its level,
+// output and hooks, so the package-level functions log through it, e.g.
+//
+//	flogger.Use(app.Logger, false)
+//
+// With useFormatter, flogger's custom formatter replaces l's one; see NewWithLogrus for the details.
+// It is safe to call while other goroutines log; entries already being written go to the previous logger.
+func Use(l *logrus.Logger, useFormatter bool) {
+	defaultLogger.Store(NewWithLogrus(l, useFormatter))
 }
 
-// init is a special function that initializes the logger when the package is imported.
-func init() {
-	// Create a new instance of the logrus logger.
-	log = logrus.New()
-
-	// Initialize the custom formatter with desired settings.
-	formatter := &customFormatter{
-		TextFormatter: &prefixed.TextFormatter{
-			ForceColors:     true,                  // Force colored output.
-			ForceFormatting: true,                  // Force formatting even if the output is not a terminal.
-			FullTimestamp:   true,                  // Include the full timestamp in the log output.
-			TimestampFormat: "2006-01-02 15:04:05", // Set the timestamp format.
-		},
-	}
+// WithFields returns an Entry carrying the given structured fields, e.g.
+//
+//	flogger.WithFields(map[string]interface{}{"user_id": 42}).Info("login")
+//
+// The returned Entry can be reused for any number of log calls.
+func WithFields(fields map[string]interface{}) *Entry {
+	return std().WithFields(fields)
+}
+
+// WithField returns an Entry carrying a single structured field, e.g.
+//
+//	flogger.WithField("request_id", id).Info("handling request")
+//
+// Building the Entry is cheap, and it can be chained with further WithField or WithFields calls.
+func WithField(key string, value interface{}) *Entry {
+	return std().WithField(key, value)
+}
+
+// WithComponent returns an Entry tagged with the given component, e.g.
+//
+//	storageLog := flogger.WithComponent("storage")
+//	storageLog.Debug("compacting %d segments", n)
+//
+// Its entries are filtered against the component's level (see SetComponentLevel), falling back to the global level.
+func WithComponent(name string) *Entry {
+	return std().WithComponent(name)
+}
+
+// WithError returns an Entry carrying err under the error field, e.g.
+//
+//	flogger.WithError(err).Error("saving order failed")
+//
+// If err wraps other errors, the message of every error in the chain is attached under the error_chain field.
+// The JSON formatter serializes the error as its Error() string.
+func WithError(err error) *Entry {
+	return std().WithError(err)
+}
 
-	// Set the custom formatter as the logger's formatter.
-	log.SetFormatter(formatter)
+// WithContext returns an Entry bound to ctx, so correlation data such as the trace ID
+// stored by ContextWithTraceID is logged as fields.
+func WithContext(ctx context.Context) *Entry {
+	return std().WithContext(ctx)
+}
 
-	// Uncomment the following line to enable caller information (file and line number) in logs.
-	// log.SetReportCaller(true)
+// WithPrefix returns an Entry whose messages are tagged with prefix, rendered highlighted before the message.
+func WithPrefix(prefix string) *Entry {
+	return std().WithPrefix(prefix)
+}
 
-	// Set the default log level to Info. Adjust this as needed for your application.
-	log.SetLevel(logrus.InfoLevel)
+// WithOutput returns an Entry whose entries are written to w in addition to the package-level logger's outputs.
+func WithOutput(w io.Writer) *Entry {
+	return std().WithOutput(w)
+}
+
+// WithGroup returns an Entry whose fields are namespaced under name, e.g. "db.rows" for the rows field of group db.
+func WithGroup(name string) *Entry {
+	return std().WithGroup(name)
+}
+
+// SetAuditOutput sets the writer of the package-level logger's audit stream; nil disables it.
+func SetAuditOutput(w io.Writer) {
+	std().SetAuditOutput(w)
+}
+
+// AuditLog writes a message to the package-level logger's audit stream, bypassing its level filtering and sampling.
+func AuditLog(format string, args ...interface{}) {
+	std().AuditLog(format, args...)
+}
+
+// Std returns a *log.Logger whose output is logged at Info level by the package-level logger.
+func Std() *log.Logger {
+	return std().Std()
+}
+
+// SetAsStdLogDefault routes the output of the standard log package's default logger into the package-level logger
+// at Info level, clearing its prefix and flags unless preserveHeader is set.
+func SetAsStdLogDefault(preserveHeader bool) {
+	std().SetAsStdLogDefault(preserveHeader)
+}
+
+// NewSlogHandler returns a slog.Handler that emits records through the package-level logger, e.g.
+//
+//	slog.SetDefault(slog.New(flogger.NewSlogHandler()))
+//
+// Levels map to the closest logrus level and attributes become fields; see Logger.SlogHandler.
+func NewSlogHandler() slog.Handler {
+	return std().SlogHandler()
+}
+
+// Writer returns an io.Writer that logs each line written to it at the given level through the package-level logger, e.g.
+//
+//	server := &http.Server{ErrorLog: log.New(flogger.Writer("error"), "", 0)}
+//
+// Trailing newlines are trimmed and empty lines are dropped. Unknown level names fall back to Info.
+func Writer(level string) io.Writer {
+	return std().Writer(level)
 }
 
 // log level functions
 
 // Info logs a message at the Info level with formatting.
-// It accepts a format string and variadic arguments, similar to fmt.Printf.
+// It accepts a format string and variadic arguments, similar to fmt.Printf; without arguments the format
+// is logged as is, so Info("50% off") needs no escaping.
 func Info(format string, args ...interface{}) {
-	log.Infof(format, args...)
+	std().Info(format, args...)
+}
+
+// Notice logs a message at the notice pseudo-level with formatting: filtered as Info, but rendered as NOTICE.
+func Notice(format string, args ...interface{}) {
+	std().Notice(format, args...)
 }
 
 // Warn logs a message at the Warn level with formatting.
 // It accepts a format string and variadic arguments, similar to fmt.Printf.
 func Warn(format string, args ...interface{}) {
-	log.Warnf(format, args...)
+	std().Warn(format, args...)
+}
+
+// Log logs a message with formatting at the level named level, chosen at runtime, e.g.
+//
+//	flogger.Log(levelForStatus(status), "%s %s: %d", r.Method, r.URL.Path, status)
+//
+// An unknown level name is logged at Info, with a one-time warning naming it.
+func Log(level, format string, args ...interface{}) {
+	std().Log(level, format, args...)
+}
+
+// LogAt logs a message with formatting at the given level, chosen at runtime.
+func LogAt(level logrus.Level, format string, args ...interface{}) {
+	std().LogAt(level, format, args...)
+}
+
+// WarnOnce logs a message at the Warn level with formatting the first time it is called with key
+// and drops it on every later call with the same key, e.g.
+//
+//	flogger.WarnOnce("legacy-config", "the %s option is deprecated, use %s", old, replacement)
+func WarnOnce(key, format string, args ...interface{}) {
+	std().WarnOnce(key, format, args...)
 }
 
 // Error logs a message at the Error level with formatting.
 // It accepts a format string and variadic arguments, similar to fmt.Printf.
 func Error(format string, args ...interface{}) {
-	log.Errorf(format, args...)
+	std().Error(format, args...)
+}
+
+// Debug logs a message at the Debug level with formatting.
+// It is a no-op unless the logger level is set to Debug or lower, and returns before formatting anything otherwise;
+// only boxing the arguments of the call may allocate, which Debug1 avoids for a single argument.
+func Debug(format string, args ...interface{}) {
+	std().Debug(format, args...)
+}
+
+// Debug1 logs a message at the Debug level formatted with a single argument, like Debug(format, arg), but without
+// any heap allocation while the Debug level is disabled: a variadic call boxes its arguments before Debug can check
+// the level, while arg is only boxed once the level is known to be enabled. Use it in hot loops.
+func Debug1[T any](format string, arg T) {
+	l := std()
+	if !l.enabled(logrus.DebugLevel) {
+		return
+	}
+	l.logf(nil, logrus.DebugLevel, format, []interface{}{arg})
+}
+
+// Trace logs a message at the Trace level with formatting.
+// It is a no-op unless the logger level is set to Trace.
+func Trace(format string, args ...interface{}) {
+	std().Trace(format, args...)
+}
+
+// Trace1 logs a message at the Trace level formatted with a single argument, like Trace(format, arg), without
+// any heap allocation while the Trace level is disabled, see Debug1.
+func Trace1[T any](format string, arg T) {
+	l := std()
+	if !l.enabled(logrus.TraceLevel) {
+		return
+	}
+	l.logf(nil, logrus.TraceLevel, format, []interface{}{arg})
+}
+
+// Fatal logs a message at the Fatal level with formatting and then terminates the process with os.Exit(1).
+// The entry passes through the custom formatter like any other level, so the crash site is preserved.
+func Fatal(format string, args ...interface{}) {
+	std().Fatal(format, args...)
+}
+
+// Panic logs a message at the Panic level with formatting and then panics with the logged entry.
+// It accepts a format string and variadic arguments, similar to fmt.Printf.
+func Panic(format string, args ...interface{}) {
+	std().Panic(format, args...)
+}
+
+// InfoContext logs a message at the Info level with formatting, including correlation data carried by ctx.
+func InfoContext(ctx context.Context, format string, args ...interface{}) {
+	std().InfoContext(ctx, format, args...)
+}
+
+// WarnContext logs a message at the Warn level with formatting, including correlation data carried by ctx.
+func WarnContext(ctx context.Context, format string, args ...interface{}) {
+	std().WarnContext(ctx, format, args...)
+}
+
+// ErrorContext logs a message at the Error level with formatting, including correlation data carried by ctx.
+func ErrorContext(ctx context.Context, format string, args ...interface{}) {
+	std().ErrorContext(ctx, format, args...)
+}
+
+// Infoln logs its operands at the Info level without formatting, separated by spaces like fmt.Sprintln.
+// Unlike Info with arguments, a literal "%" in the operands is logged as is.
+func Infoln(args ...interface{}) {
+	std().Infoln(args...)
+}
+
+// Warnln logs its operands at the Warn level without formatting, separated by spaces like fmt.Sprintln.
+func Warnln(args ...interface{}) {
+	std().Warnln(args...)
+}
+
+// Errorln logs its operands at the Error level without formatting, separated by spaces like fmt.Sprintln.
+func Errorln(args ...interface{}) {
+	std().Errorln(args...)
+}
+
+// Infow logs a message at the Info level with the given alternating keys and values as fields, e.g.
+//
+//	flogger.Infow("saved", "id", 42, "dur", d)
+//
+// The message is logged as is, without formatting. A dangling key without a value is logged with the value "(MISSING)".
+func Infow(msg string, keysAndValues ...interface{}) {
+	std().Infow(msg, keysAndValues...)
+}
+
+// Warnw logs a message at the Warn level with the given alternating keys and values as fields.
+func Warnw(msg string, keysAndValues ...interface{}) {
+	std().Warnw(msg, keysAndValues...)
+}
+
+// Errorw logs a message at the Error level with the given alternating keys and values as fields.
+func Errorw(msg string, keysAndValues ...interface{}) {
+	std().Errorw(msg, keysAndValues...)
+}
+
+// InfoFunc logs the message returned by fn at the Info level, calling fn only if the level is enabled.
+func InfoFunc(fn func() string) {
+	std().InfoFunc(fn)
+}
+
+// WarnFunc logs the message returned by fn at the Warn level, calling fn only if the level is enabled.
+func WarnFunc(fn func() string) {
+	std().WarnFunc(fn)
+}
+
+// ErrorFunc logs the message returned by fn at the Error level, calling fn only if the level is enabled.
+func ErrorFunc(fn func() string) {
+	std().ErrorFunc(fn)
+}
+
+// DebugFunc logs the message returned by fn at the Debug level, calling fn only if the level is enabled.
+func DebugFunc(fn func() string) {
+	std().DebugFunc(fn)
+}
+
+// configuration functions
+
+// SetLevel sets the minimum level that will be logged.
+// It accepts level names such as "trace", "debug", "info", "warn", "error", "fatal" and "panic" (case-insensitive)
+// and returns an error if the name is not recognized, leaving the current level untouched.
+func SetLevel(level string) error {
+	return std().SetLevel(level)
+}
+
+// SetLevelFor sets the global level for the duration d and then restores the previous level.
+// It returns an error for unknown levels.
+func SetLevelFor(level string, d time.Duration) error {
+	return std().SetLevelFor(level, d)
+}
+
+// CancelLevelFor restores the level set before SetLevelFor right away.
+func CancelLevelFor() {
+	std().CancelLevelFor()
+}
+
+// SetComponentLevel sets the minimum level of entries tagged with the component by WithComponent,
+// e.g. "debug" for the storage component while everything else logs at "info".
+// It returns an error if the level name is not recognized.
+func SetComponentLevel(component, level string) error {
+	return std().SetComponentLevel(component, level)
+}
+
+// GetLevel returns the name of the current minimum log level (e.g. "info").
+func GetLevel() string {
+	return std().GetLevel()
+}
+
+// IsLevelEnabled reports whether entries at the given level would be emitted, false for unknown levels.
+func IsLevelEnabled(level string) bool {
+	return std().IsLevelEnabled(level)
+}
+
+// SetOutput sets the writer that log entries are written to (stderr by default).
+// It is useful for redirecting logs to a file, a buffer or any custom sink, and replaces every previously configured writer.
+func SetOutput(w io.Writer) {
+	std().SetOutput(w)
+}
+
+// SetOutputs replaces the configured writers with the given ones; every entry is written to all of them.
+// It also turns off SplitOutput.
+func SetOutputs(writers ...io.Writer) {
+	std().SetOutputs(writers...)
+}
+
+// SplitOutput writes Trace, Debug and Info entries to stdout and Warn, Error, Fatal and Panic entries to stderr,
+// so orchestrators can tell them apart. It stays in effect until SetOutput or SetOutputs is called.
+func SplitOutput() {
+	std().SplitOutput()
+}
+
+// AddOutput adds a writer to the configured ones, so entries are written to it as well, e.g.
+//
+//	flogger.AddOutput(file) // keeps logging to stderr and also writes to file
+func AddOutput(w io.Writer) {
+	std().AddOutput(w)
+}
+
+// StandardLogger returns the logrus logger behind the package-level functions, an escape hatch for power users
+// who need a logrus feature flogger does not wrap (custom exit functions, hooks, formatters, ...).
+// Changes made directly on it bypass flogger's configuration, see Logger.Logrus.
+func StandardLogger() *logrus.Logger {
+	return std().Logrus()
+}
+
+// RegisterExitHandler registers a function to run before the process exits after a Fatal entry,
+// e.g. to flush buffers or close connections. Handlers are shared by every Logger (they are logrus' own),
+// run in registration order, and a panicking handler does not prevent the others from running.
+func RegisterExitHandler(fn func()) {
+	logrus.RegisterExitHandler(fn)
+}
+
+// SetExitCode sets the code the process exits with after a Fatal entry (1 by default).
+func SetExitCode(code int) {
+	std().SetExitCode(code)
+}
+
+// SetExitFunc replaces the function called with the exit code after a Fatal entry (os.Exit by default).
+// Tests can substitute a function that records the code instead of terminating the process; Fatal then returns.
+// The outputs are flushed before fn is called either way.
+func SetExitFunc(fn func(code int)) {
+	std().SetExitFunc(fn)
+}
+
+// AddFieldProvider registers fn to compute fields added to every entry of the package-level logger, e.g.
+//
+//	flogger.AddFieldProvider(func() map[string]interface{} {
+//		var m runtime.MemStats
+//		runtime.ReadMemStats(&m)
+//		return map[string]interface{}{"mem_alloc": m.Alloc}
+//	})
+//
+// See Logger.AddFieldProvider for the precedence rules.
+func AddFieldProvider(fn func() map[string]interface{}) {
+	std().AddFieldProvider(fn)
+}
+
+// AddContextExtractor registers fn to pull correlation fields out of the context of the package-level logger's
+// entries, see Logger.AddContextExtractor.
+func AddContextExtractor(fn func(ctx context.Context) map[string]interface{}) {
+	std().AddContextExtractor(fn)
+}
+
+// SetDefaultFields replaces the fields attached to every log entry, e.g. the service name and version.
+// Fields passed to WithFields or WithField override a default field with the same key; an empty map clears them.
+func SetDefaultFields(fields map[string]interface{}) {
+	std().SetDefaultFields(fields)
+}
+
+// SetDuplicateFieldPolicy decides what happens when a default field of the package-level logger and a field set
+// on the entry share a key.
+func SetDuplicateFieldPolicy(policy string) error {
+	return std().SetDuplicateFieldPolicy(policy)
+}
+
+// AddDefaultField attaches a single field to every log entry, keeping the other default fields.
+func AddDefaultField(key string, value interface{}) {
+	std().AddDefaultField(key, value)
+}
+
+// RedactKeys registers field keys whose values are replaced with "***" before formatting, e.g.
+//
+//	flogger.RedactKeys("password", "token", "authorization")
+//
+// Keys are matched case-insensitively and apply to per-call and default fields alike; calls accumulate.
+func RedactKeys(keys ...string) {
+	std().RedactKeys(keys...)
+}
+
+// SetStructuredStack attaches the current stack to every Error, Fatal and Panic entry of the package-level logger
+// as the frames field, a slice of StackFrame objects in JSON.
+func SetStructuredStack(enabled bool) {
+	std().SetStructuredStack(enabled)
+}
+
+// SetStackTraceLevel attaches a stack trace as the stacktrace field to every entry at or above the given level.
+// An empty level turns it off again.
+func SetStackTraceLevel(level string) error {
+	return std().SetStackTraceLevel(level)
+}
+
+// SetMaxFieldLength truncates field values rendering longer than n characters, appending "...(truncated)".
+// Zero or less means no limit.
+func SetMaxFieldLength(n int) {
+	std().SetMaxFieldLength(n)
+}
+
+// SetMaxMessageLength truncates messages longer than n characters, appending "...(truncated)".
+// Zero or less means no limit.
+func SetMaxMessageLength(n int) {
+	std().SetMaxMessageLength(n)
+}
+
+// AddHook registers a logrus hook on the package-level logger, e.g. to ship errors to Sentry or count entries for metrics.
+// Hooks only fire for entries at or above the configured level, see Logger.AddHook.
+func AddHook(hook logrus.Hook) {
+	std().AddHook(hook)
+}
+
+// NewTestHook installs a TestHook on the package-level logger, recording its entries until Remove is called.
+func NewTestHook() *TestHook {
+	return std().NewTestHook()
+}
+
+// SetReportCaller enables or disables the func and file fields describing where each entry was logged from.
+// It is disabled by default because resolving the caller has a small cost on every log call.
+func SetReportCaller(enabled bool) {
+	std().SetReportCaller(enabled)
+}
+
+// SetColors forces colored text output on or off.
+// Until it is called, colors are used only when the output is a terminal, so files and CI logs stay free of ANSI codes.
+func SetColors(enabled bool) {
+	std().SetColors(enabled)
+}
+
+// RegisterLevel registers a pseudo-level on the package-level logger ranking just below the level below,
+// see Logger.RegisterLevel.
+func RegisterLevel(name, below string) error {
+	return std().RegisterLevel(name, below)
+}
+
+// SetLevelColor sets the color of the level's label and field keys in colored text output, e.g. "red+b".
+func SetLevelColor(level, color string) error {
+	return std().SetLevelColor(level, color)
+}
+
+// SetFullFunctionName keeps the fully qualified caller function in the func field when enabled,
+// e.g. "github.com/me/app/internal/svc.(*Server).Handle" instead of the default "svc.(*Server).Handle".
+func SetFullFunctionName(enabled bool) {
+	std().SetFullFunctionName(enabled)
+}
+
+// SetCallerSkipPackages makes the package-level logger's caller reporting pass over the frames whose function
+// starts with one of prefixes.
+func SetCallerSkipPackages(prefixes ...string) {
+	std().SetCallerSkipPackages(prefixes...)
+}
+
+// SetCallerPathMode selects how the package-level logger's file field renders the caller's file,
+// e.g. CallerPathPackage for "svc/handler.go:42". It returns an error for an unknown mode.
+func SetCallerPathMode(mode string) error {
+	return std().SetCallerPathMode(mode)
+}
+
+// SetExtractKVFromMessage promotes the key=value tokens at the end of the package-level logger's messages to
+// fields in JSON output.
+func SetExtractKVFromMessage(enabled bool) {
+	std().SetExtractKVFromMessage(enabled)
+}
+
+// SetFieldValueFormat selects how the package-level logger's text output renders maps, structs, slices and arrays.
+func SetFieldValueFormat(format string) error {
+	return std().SetFieldValueFormat(format)
+}
+
+// SetCallerFields selects which of the func and file caller fields are added while SetReportCaller is enabled.
+func SetCallerFields(includeFunc, includeFile bool) {
+	std().SetCallerFields(includeFunc, includeFile)
+}
+
+// SetCallerFieldKeys sets the keys of the caller fields, "func" and "file" by default. An empty key keeps the default.
+func SetCallerFieldKeys(funcKey, fileKey string) {
+	std().SetCallerFieldKeys(funcKey, fileKey)
+}
+
+// SetCallerInPrefix renders the func and file fields in the text output's header, right after the timestamp.
+func SetCallerInPrefix(enabled bool) {
+	std().SetCallerInPrefix(enabled)
+}
+
+// SetAlignMessages left-justifies the level label of the package-level logger's text output to a fixed width,
+// so messages line up in one column.
+func SetAlignMessages(enabled bool) {
+	std().SetAlignMessages(enabled)
+}
+
+// SetFieldSeparator sets what the package-level logger's text output writes before every field, a single space
+// by default.
+func SetFieldSeparator(sep string) {
+	std().SetFieldSeparator(sep)
+}
+
+// SetFieldColors sets the ansi styles of field keys and values in the package-level logger's colored text output.
+func SetFieldColors(keyColor, valueColor string) error {
+	return std().SetFieldColors(keyColor, valueColor)
+}
+
+// SetFieldOrder renders the given field keys first, in this order, in text output, e.g.
+//
+//	flogger.SetFieldOrder("request_id", "user_id")
+//
+// The remaining fields follow sorted by key, and the caller fields (file and func) come last unless listed.
+func SetFieldOrder(keys ...string) {
+	std().SetFieldOrder(keys...)
+}
+
+// SetTimestampFormat sets the layout used to render timestamps, e.g. time.RFC3339Nano.
+// An empty layout disables timestamps entirely, which is useful when journald or similar already timestamps each line.
+func SetTimestampFormat(layout string) {
+	std().SetTimestampFormat(layout)
+}
+
+// SetClock sets the function returning the time of every entry, time.Now by default. A nil fn restores time.Now.
+func SetClock(fn func() time.Time) {
+	std().SetClock(fn)
+}
+
+// SetLineTransform sets a function applied to every line formatted by the package-level logger before it is written.
+func SetLineTransform(fn func([]byte) []byte) {
+	std().SetLineTransform(fn)
+}
+
+// SetUTC renders timestamps in UTC when enabled, and in local time (the default) otherwise.
+func SetUTC(enabled bool) {
+	std().SetUTC(enabled)
+}
+
+// SetReportGoroutineID adds the ID of the logging goroutine as the goroutine field to every entry when enabled,
+// which helps correlating lines when debugging concurrency issues. It is off by default because of its cost.
+func SetReportGoroutineID(enabled bool) {
+	std().SetReportGoroutineID(enabled)
+}
+
+// SetFormatter selects the output format: FormatText ("text", the default), FormatJSON ("json"), FormatLogfmt ("logfmt")
+// or FormatGCP ("gcp", JSON for Google Cloud Logging).
+// It returns an error for unknown formats, leaving the current format untouched.
+func SetFormatter(format string) error {
+	return std().SetFormatter(format)
+}
+
+// SetJSONMessageKey renames the key of the message in the package-level logger's JSON output, "msg" by default.
+func SetJSONMessageKey(key string) {
+	std().SetJSONMessageKey(key)
+}
+
+// SetJSONLevelKey renames the key of the level in the package-level logger's JSON output, "level" by default.
+func SetJSONLevelKey(key string) {
+	std().SetJSONLevelKey(key)
+}
+
+// SetJSONTimeKey renames the key of the timestamp in the package-level logger's JSON output, "time" by default.
+func SetJSONTimeKey(key string) {
+	std().SetJSONTimeKey(key)
+}
+
+// UseJSONFormatter switches the output to JSON, keeping the func and file fields.
+// It is a shorthand for SetFormatter(FormatJSON).
+func UseJSONFormatter() {
+	std().UseJSONFormatter()
+}
+
+// AutoFormat selects the colored text format if the output is an interactive terminal and JSON otherwise.
+func AutoFormat() {
+	std().AutoFormat()
+}
+
+// SetBatchOutput redirects the package-level logger's output to a BatchWriter for sink, see NewBatchWriter.
+func SetBatchOutput(sink func([][]byte) error, maxBatch int, flushInterval time.Duration) {
+	std().SetBatchOutput(sink, maxBatch, flushInterval)
+}
+
+// SetTCPOutput sends the package-level logger's entries as newline-delimited JSON over a TCP connection to addr,
+// reconnecting in the background when it fails; see Logger.SetTCPOutput.
+func SetTCPOutput(addr string) error {
+	return std().SetTCPOutput(addr)
+}
+
+// SetFallbackOutput sets the writer that receives the entries an output fails to write, typically os.Stderr.
+// A nil writer removes the fallback.
+func SetFallbackOutput(w io.Writer) {
+	std().SetFallbackOutput(w)
+}
+
+// SetFileOutput redirects the log output to a rotating file, see NewFileWriter for the parameters.
+// To keep logging to stderr as well, combine the writers instead:
+//
+//	w, err := flogger.NewFileWriter("/var/log/app/app.log", 100, 5, 30)
+//	flogger.SetOutput(io.MultiWriter(os.Stderr, w))
+func SetFileOutput(path string, maxSizeMB, maxBackups, maxAgeDays int) error {
+	return std().SetFileOutput(path, maxSizeMB, maxBackups, maxAgeDays)
+}
+
+// SetFileColors keeps the ANSI colors in the files opened by later SetFileOutput calls when enabled;
+// they are stripped by default.
+func SetFileColors(enabled bool) {
+	std().SetFileColors(enabled)
+}
+
+// Configure applies every set field of cfg to the package-level logger in one step, e.g.
+//
+//	err := flogger.Configure(flogger.Config{Level: "debug", Format: flogger.FormatJSON})
+//
+// Zero values leave the corresponding setting unchanged. It returns an error without changing anything
+// if Level or Format is invalid.
+func Configure(cfg Config) error {
+	return std().Configure(cfg)
+}
+
+// EnableRingBuffer keeps the package-level logger's last capacity formatted entries in memory for DumpRecent;
+// a capacity of zero or less disables it.
+func EnableRingBuffer(capacity int) {
+	std().EnableRingBuffer(capacity)
+}
+
+// DumpRecent writes the entries kept by EnableRingBuffer to w, oldest first, e.g. from a /debug/logs handler:
+//
+//	http.HandleFunc("/debug/logs", func(w http.ResponseWriter, r *http.Request) { flogger.DumpRecent(w) })
+func DumpRecent(w io.Writer) error {
+	return std().DumpRecent(w)
+}
+
+// Snapshot returns the current configuration of the package-level logger, see Logger.Snapshot.
+func Snapshot() Config {
+	return std().Snapshot()
+}
+
+// Restore puts back a configuration of the package-level logger captured by Snapshot, see Logger.Restore.
+func Restore(cfg Config) error {
+	return std().Restore(cfg)
+}
+
+// CaptureOutput runs fn with the package-level logger's output redirected to an in-memory buffer
+// and returns what was logged, which makes it easy to assert on logs in tests:
+//
+//	out := flogger.CaptureOutput(func() { doWork(badInput) })
+//	if !bytes.Contains(out, []byte("WARN")) { t.Error("expected a warning") }
+//
+// The previous outputs are restored afterwards, even if fn panics.
+func CaptureOutput(fn func()) []byte {
+	return std().CaptureOutput(fn)
+}
+
+// Flush flushes every configured writer that buffers its output, and is a no-op for plain writers.
+// Call it in a deferred cleanup in main so no pending entries are lost:
+//
+//	defer flogger.Flush()
+func Flush() error {
+	return std().Flush()
+}
+
+// EnableAsync moves writing entries off the caller's goroutine, queueing up to bufferSize entries for a background
+// goroutine; see Logger.EnableAsync. A bufferSize of zero or less turns it off again.
+// Call Flush before exiting, so pending entries are written.
+func EnableAsync(bufferSize int) {
+	std().EnableAsync(bufferSize)
+}
+
+// SetAsyncPolicy decides what happens when the asynchronous buffer is full, see AsyncBlock and AsyncDrop.
+func SetAsyncPolicy(policy AsyncPolicy) {
+	std().SetAsyncPolicy(policy)
+}
+
+// SetSampling limits identical messages to n per interval to protect disks and downstream ingestion from log floods, e.g.
+//
+//	flogger.SetSampling(10, time.Second)
+//
+// When a window closes, a summary reporting how many messages were suppressed is logged.
+// An n or interval of zero or less turns sampling off again.
+func SetSampling(n int, interval time.Duration) {
+	std().SetSampling(n, interval)
+}
+
+// SetLevelSampling emits the package-level logger's entries of each listed level with the given probability.
+func SetLevelSampling(rates map[string]float64) error {
+	return std().SetLevelSampling(rates)
+}
+
+// SetSamplerTick lets the package-level logger's first messages with the same format string through in every
+// interval, then one in every thereafter; see Logger.SetSamplerTick.
+func SetSamplerTick(first, thereafter int, interval time.Duration) {
+	std().SetSamplerTick(first, thereafter, interval)
+}
+
+// SetDedup collapses identical consecutive entries within window into the first one and a
+// "last message repeated N times" summary. A window of zero or less turns deduplication off again.
+func SetDedup(window time.Duration) {
+	std().SetDedup(window)
+}
+
+// RecoverAndLog recovers a panic and logs it at the Error level with the panic value and stack trace as fields.
+// It must be deferred directly, e.g. at the top of a worker goroutine:
+//
+//	go func() {
+//		defer flogger.RecoverAndLog()
+//		work()
+//	}()
+//
+// The panic is swallowed unless SetRepanic(true) was called.
+func RecoverAndLog() {
+	// recover only works when called directly by the deferred function, so it cannot be delegated to std.
+	if r := recover(); r != nil {
+		std().logRecovered(r)
+	}
+}
+
+// SetRepanic makes RecoverAndLog continue panicking after logging when enabled, instead of swallowing the panic.
+func SetRepanic(enabled bool) {
+	std().SetRepanic(enabled)
+}
+
+// SetSyslogOutput sends entries to a syslog daemon instead of the configured writers, with a priority matching
+// each entry's level (Error→LOG_ERR, Warn→LOG_WARNING, Info→LOG_INFO, ...; see Logger.SetSyslogOutput).
+// An empty network and addr connect to the local daemon. It returns ErrSyslogUnsupported on Windows and Plan 9.
+func SetSyslogOutput(network, addr, tag string) error {
+	return std().SetSyslogOutput(network, addr, tag)
+}
+
+// Disable turns logging off entirely until Enable is called, dropping entries before they are formatted.
+func Disable() {
+	std().Disable()
+}
+
+// Enable turns logging back on after Disable.
+func Enable() {
+	std().Enable()
+}
+
+// SetOmitEmptyFields drops the package-level logger's fields whose value is nil, an empty string
+// or an empty slice or map from the output.
+func SetOmitEmptyFields(enabled bool) {
+	std().SetOmitEmptyFields(enabled)
+}
+
+// PrettyJSONFields makes the text formatter indent the values of the given fields that hold a JSON object or array.
+func PrettyJSONFields(keys ...string) {
+	std().PrettyJSONFields(keys...)
+}
+
+// SetRelativeTimestamps makes the text formatter render the time elapsed since the first entry (or SetTimestampEpoch)
+// instead of the wall-clock time. JSON output is not affected.
+func SetRelativeTimestamps(enabled bool) {
+	std().SetRelativeTimestamps(enabled)
+}
+
+// SetTimestampEpoch sets the reference time of relative timestamps, see SetRelativeTimestamps.
+func SetTimestampEpoch(epoch time.Time) {
+	std().SetTimestampEpoch(epoch)
+}
+
+// Child returns a logger that adds fields to every entry and shares the package-level logger's configuration.
+func Child(fields map[string]interface{}) *Logger {
+	return std().Child(fields)
+}
+
+// Close releases the resources held by the package-level logger, see Logger.Close. Defer it in main.
+func Close() error {
+	return std().Close()
+}
+
+// HTTPMiddleware returns a handler that serves requests with next and logs each one through the package-level logger,
+// with its method, path, status code and duration as fields (at the Error level for 5xx responses).
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return std().HTTPMiddleware(next)
 }
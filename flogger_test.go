@@ -0,0 +1,89 @@
+package flogger
+
+import (
+	"github.com/sirupsen/logrus"
+	"io"
+	"sync"
+	"testing"
+)
+
+// withStdLevel sets the level of the package-level logger for the duration of the test.
+func withStdLevel(tb testing.TB, level string) {
+	tb.Helper()
+	previous := GetLevel()
+	if err := SetLevel(level); err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { _ = SetLevel(previous) })
+}
+
+func TestUseWhileLogging(t *testing.T) {
+	previous := std()
+	t.Cleanup(func() { defaultLogger.Store(previous) })
+	defaultLogger.Store(New())
+	std().SetOutput(io.Discard)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					Info("logging while the default logger is replaced")
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 50; i++ {
+		l := logrus.New()
+		l.SetOutput(io.Discard)
+		Use(l, i%2 == 0)
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func TestDisabledSingleArgumentDoesNotAllocate(t *testing.T) {
+	withStdLevel(t, "info")
+	n := 42
+
+	if allocs := testing.AllocsPerRun(100, func() { Debug1("value %d", n) }); allocs != 0 {
+		t.Errorf("Debug1 allocates %v times per call, want 0", allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { Trace1("value %d", n) }); allocs != 0 {
+		t.Errorf("Trace1 allocates %v times per call, want 0", allocs)
+	}
+}
+
+// BenchmarkDebugDisabled measures a disabled variadic Debug call, whose argument is boxed before the level is checked.
+func BenchmarkDebugDisabled(b *testing.B) {
+	withStdLevel(b, "info")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Debug("value %d", i)
+	}
+}
+
+// BenchmarkDebug1Disabled measures a disabled Debug1 call, which should not allocate.
+func BenchmarkDebug1Disabled(b *testing.B) {
+	withStdLevel(b, "info")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Debug1("value %d", i)
+	}
+}
+
+// BenchmarkTrace1Disabled measures a disabled Trace1 call, which should not allocate.
+func BenchmarkTrace1Disabled(b *testing.B) {
+	withStdLevel(b, "info")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Trace1("value %d", i)
+	}
+}
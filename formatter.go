@@ -0,0 +1,549 @@
+package flogger
+
+import (
+	"fmt"
+	"github.com/mgutz/ansi"
+	"github.com/sirupsen/logrus"
+	prefixed "github.com/x-cray/logrus-prefixed-formatter"
+	"path"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// supported output formats accepted by SetFormatter.
+const (
+	// FormatText renders entries with the colored prefixed text formatter (the default).
+	FormatText = "text"
+	// FormatJSON renders entries as one JSON object per line.
+	FormatJSON = "json"
+	// FormatLogfmt renders entries as one line of logfmt key=value pairs.
+	FormatLogfmt = "logfmt"
+	// FormatGCP renders entries as JSON understood by Google Cloud Logging.
+	FormatGCP = "gcp"
+)
+
+// caller fields added by the custom formatter.
+const (
+	// funcField holds the function the entry was logged from.
+	funcField = "func"
+	// fileField holds the file and line the entry was logged from.
+	fileField = "file"
+)
+
+// caller path modes accepted by SetCallerPathMode, selecting how the file field renders the caller's file.
+const (
+	// CallerPathBasename renders the file name only, e.g. "handler.go:42" (the default).
+	CallerPathBasename = "basename"
+	// CallerPathPackage renders the file name with its directory, e.g. "svc/handler.go:42".
+	CallerPathPackage = "package"
+	// CallerPathFull renders the full path of the file as recorded at build time.
+	CallerPathFull = "full"
+)
+
+// field value formats accepted by SetFieldValueFormat, selecting how text output renders maps, structs and slices.
+const (
+	// FieldValueDefault renders values with %+v, e.g. "map[a:1 b:2]" (the default).
+	FieldValueDefault = "default"
+	// FieldValueJSON renders maps, structs, slices and arrays as JSON, e.g. `{"a":1,"b":2}`.
+	FieldValueJSON = "json"
+)
+
+// defaultTimestampFormat is the timestamp layout of the text formatter unless another one is configured.
+const defaultTimestampFormat = "2006-01-02 15:04:05"
+
+// colorMode selects whether the text formatter emits ANSI colors.
+type colorMode int
+
+const (
+	// colorsAuto enables colors only when the output is a terminal.
+	colorsAuto colorMode = iota
+	// colorsOn forces colors regardless of the output.
+	colorsOn
+	// colorsOff disables colors regardless of the output.
+	colorsOff
+)
+
+// formatterOptions holds the settings the custom formatter is built from.
+// A Logger keeps one copy and rebuilds its formatter whenever an option changes.
+type formatterOptions struct {
+	// format is the selected output format, one of the Format* constants.
+	format string
+	// colors selects whether the text output is colored.
+	colors colorMode
+	// timestampFormat is the configured timestamp layout, empty for the formatter's default.
+	timestampFormat string
+	// disableTimestamp omits the timestamp from every entry.
+	disableTimestamp bool
+	// utc renders timestamps in UTC instead of local time.
+	utc bool
+	// fullFunctionName keeps the fully qualified caller function instead of trimming it to the last package.
+	fullFunctionName bool
+	// callerPath is how the file field renders the caller's file, one of the CallerPath* constants.
+	callerPath string
+	// includeFunc adds the func field to entries with caller information.
+	includeFunc bool
+	// includeFile adds the file field to entries with caller information.
+	includeFile bool
+	// funcKey is the key of the func field.
+	funcKey string
+	// fileKey is the key of the file field.
+	fileKey string
+	// fieldOrder lists the field keys rendered first by the text formatter.
+	fieldOrder []string
+	// levelColors maps levels to the ansi style of their label and field keys, replacing the default colors.
+	levelColors map[logrus.Level]string
+	// levelNameColors maps registered level names to the ansi style of their label and field keys.
+	levelNameColors map[string]string
+	// prettyJSONKeys lists the fields whose JSON values the text formatter indents.
+	prettyJSONKeys []string
+	// callerInPrefix makes the text formatter render the caller after the timestamp instead of as fields.
+	callerInPrefix bool
+	// relativeTimestamps makes the text formatter render the time elapsed since epoch instead of the wall-clock time.
+	relativeTimestamps bool
+	// epoch is the reference time of relative timestamps, shared by every formatter built from these options.
+	epoch *relativeEpoch
+	// omitEmpty drops the fields whose value is nil, an empty string or an empty slice or map.
+	omitEmpty bool
+	// alignMessages makes the text formatter pad level labels to a fixed width, so messages line up.
+	alignMessages bool
+	// fieldSeparator is written before every field by the text formatter, empty for a single space.
+	fieldSeparator string
+	// extractKV promotes the key=value tokens at the end of messages to fields in JSON output.
+	extractKV bool
+	// valueFormat is how the text formatter renders complex field values, one of the FieldValue* constants.
+	valueFormat string
+	// fieldKeyColor is the ansi style of field keys in colored text output, empty for the level's color.
+	fieldKeyColor string
+	// fieldValueColor is the ansi style of field values in colored text output, empty to leave them uncolored.
+	fieldValueColor string
+	// jsonMessageKey is the key of the message in JSON output, empty for logrus' "msg".
+	jsonMessageKey string
+	// jsonLevelKey is the key of the level in JSON output, empty for logrus' "level".
+	jsonLevelKey string
+	// jsonTimeKey is the key of the timestamp in JSON output, empty for logrus' "time".
+	jsonTimeKey string
+}
+
+// defaultFormatterOptions returns the options used by a freshly created Logger.
+func defaultFormatterOptions() formatterOptions {
+	return formatterOptions{
+		format:      FormatText,
+		includeFunc: true,
+		includeFile: true,
+		callerPath:  CallerPathBasename,
+		valueFormat: FieldValueDefault,
+		funcKey:     funcField,
+		fileKey:     fileField,
+		epoch:       &relativeEpoch{},
+	}
+}
+
+// validateFormat returns an error if format is not one of the supported output formats.
+func validateFormat(format string) error {
+	switch format {
+	case FormatText, FormatJSON, FormatLogfmt, FormatGCP:
+		return nil
+	default:
+		return fmt.Errorf("flogger: unknown log format %q", format)
+	}
+}
+
+// customFormatter is a custom log formatter that wraps either the text formatter or the logrus.JSONFormatter.
+// It adds additional fields like function name and file location to the log output.
+type customFormatter struct {
+	// formatter is the underlying formatter that renders the entry once the custom fields have been added.
+	formatter logrus.Formatter
+	// utc converts the entry time to UTC before formatting.
+	utc bool
+	// fullFunctionName keeps the fully qualified caller function in the func field.
+	fullFunctionName bool
+	// callerPath is how the file field renders the caller's file, one of the CallerPath* constants.
+	callerPath string
+	// includeFunc adds the func field when the entry has caller information.
+	includeFunc bool
+	// includeFile adds the file field when the entry has caller information.
+	includeFile bool
+	// funcKey is the key of the func field.
+	funcKey string
+	// fileKey is the key of the file field.
+	fileKey string
+	// keepCaller hands the caller to the underlying formatter instead of adding the func and file fields.
+	keepCaller bool
+	// jsonLevelKey is the key of the level in the JSON formatter's output, set for that format only.
+	jsonLevelKey string
+	// omitEmpty drops the fields whose value is nil, an empty string or an empty slice or map.
+	omitEmpty bool
+	// extractKV promotes the key=value tokens at the end of the message to fields.
+	extractKV bool
+}
+
+// newFormatter creates the custom formatter for the given options.
+func newFormatter(opts formatterOptions) *customFormatter {
+	// JSON output is meant for machines, so colors and prefix formatting do not apply.
+	// An empty layout makes the JSON formatter fall back to RFC3339.
+	if opts.format == FormatJSON {
+		// Rename the standard keys that have another name configured.
+		fieldMap := logrus.FieldMap{}
+		if opts.jsonMessageKey != "" {
+			fieldMap[logrus.FieldKeyMsg] = opts.jsonMessageKey
+		}
+		jsonLevelKey := logrus.FieldKeyLevel
+		if opts.jsonLevelKey != "" {
+			fieldMap[logrus.FieldKeyLevel] = opts.jsonLevelKey
+			jsonLevelKey = opts.jsonLevelKey
+		}
+		if opts.jsonTimeKey != "" {
+			fieldMap[logrus.FieldKeyTime] = opts.jsonTimeKey
+		}
+		return &customFormatter{
+			formatter: &logrus.JSONFormatter{
+				TimestampFormat:  opts.timestampFormat,
+				DisableTimestamp: opts.disableTimestamp,
+				FieldMap:         fieldMap,
+			},
+			utc:              opts.utc,
+			omitEmpty:        opts.omitEmpty,
+			fullFunctionName: opts.fullFunctionName,
+			callerPath:       opts.callerPath,
+			includeFunc:      opts.includeFunc,
+			includeFile:      opts.includeFile,
+			funcKey:          opts.funcKey,
+			fileKey:          opts.fileKey,
+			jsonLevelKey:     jsonLevelKey,
+			extractKV:        opts.extractKV,
+		}
+	}
+
+	// Cloud Logging expects the caller as a sourceLocation object rather than the func and file fields,
+	// so the entry keeps its caller for the GCP formatter to render.
+	if opts.format == FormatGCP {
+		return &customFormatter{
+			formatter:  &gcpFormatter{includeSourceLocation: opts.includeFunc || opts.includeFile},
+			utc:        opts.utc,
+			omitEmpty:  opts.omitEmpty,
+			keepCaller: true,
+		}
+	}
+
+	// logfmt is meant for machines too, and renders the caller fields after the user's ones like the text formatter.
+	if opts.format == FormatLogfmt {
+		return &customFormatter{
+			formatter: &logfmtFormatter{
+				timestampFormat:  opts.timestampFormat,
+				disableTimestamp: opts.disableTimestamp,
+				trailingKeys:     []string{opts.fileKey, opts.funcKey},
+			},
+			utc:              opts.utc,
+			omitEmpty:        opts.omitEmpty,
+			fullFunctionName: opts.fullFunctionName,
+			callerPath:       opts.callerPath,
+			includeFunc:      opts.includeFunc,
+			includeFile:      opts.includeFile,
+			funcKey:          opts.funcKey,
+			fileKey:          opts.fileKey,
+		}
+	}
+
+	timestampFormat := opts.timestampFormat
+	if timestampFormat == "" {
+		timestampFormat = defaultTimestampFormat
+	}
+
+	// Relative timestamps are rendered by the text formatter itself, in place of the header's timestamp.
+	var epoch *relativeEpoch
+	if opts.relativeTimestamps && !opts.disableTimestamp {
+		epoch = opts.epoch
+	}
+	// The caller must follow the timestamp, so the text formatter renders the timestamp itself for that mode as well.
+	var ownTimestampFormat string
+	if opts.callerInPrefix && !opts.disableTimestamp && epoch == nil {
+		ownTimestampFormat = timestampFormat
+	}
+	hideTimestamp := opts.disableTimestamp || epoch != nil || opts.callerInPrefix
+
+	// newHeader creates the prefixed formatter rendering the line header, with debugStyle as the color of the
+	// Debug level label, which the prefixed formatter also uses for the Trace level.
+	newHeader := func(debugStyle string) *prefixed.TextFormatter {
+		header := &prefixed.TextFormatter{
+			ForceColors:      opts.colors == colorsOn,  // Force colored output, otherwise colors depend on the output being a terminal.
+			DisableColors:    opts.colors == colorsOff, // Never color the output.
+			ForceFormatting:  true,                     // Force formatting even if the output is not a terminal.
+			DisableTimestamp: hideTimestamp,            // Omit the timestamp when the surrounding system adds its own.
+			FullTimestamp:    true,                     // Include the full timestamp in the log output.
+			TimestampFormat:  timestampFormat,          // Set the timestamp format.
+		}
+		header.SetColorScheme(&prefixed.ColorScheme{
+			InfoLevelStyle:  levelStyle(opts.levelColors, logrus.InfoLevel),
+			WarnLevelStyle:  levelStyle(opts.levelColors, logrus.WarnLevel),
+			ErrorLevelStyle: levelStyle(opts.levelColors, logrus.ErrorLevel),
+			FatalLevelStyle: levelStyle(opts.levelColors, logrus.FatalLevel),
+			PanicLevelStyle: levelStyle(opts.levelColors, logrus.PanicLevel),
+			DebugLevelStyle: debugStyle,
+			PrefixStyle:     prefixStyle,
+			TimestampStyle:  timestampStyle,
+		})
+		return header
+	}
+
+	// Trace entries need their own header when the Trace color differs from the Debug one.
+	header := newHeader(levelStyle(opts.levelColors, logrus.DebugLevel))
+	var traceHeader *prefixed.TextFormatter
+	if _, ok := opts.levelColors[logrus.TraceLevel]; ok {
+		traceHeader = newHeader(levelStyle(opts.levelColors, logrus.TraceLevel))
+	}
+
+	return &customFormatter{
+		formatter: &textFormatter{
+			header:          header,
+			traceHeader:     traceHeader,
+			levelColors:     opts.levelColors,
+			levelNameColors: opts.levelNameColors,
+			colors:          opts.colors,
+			fieldOrder:      opts.fieldOrder,
+			trailingKeys:    []string{opts.fileKey, opts.funcKey}, // Keep the caller after the user's fields.
+			funcKey:         opts.funcKey,
+			fileKey:         opts.fileKey,
+			prettyJSON:      prettyJSONKeys(opts.prettyJSONKeys),
+			epoch:           epoch,
+			timestampFormat: ownTimestampFormat,
+			callerInPrefix:  opts.callerInPrefix,
+			alignMessages:   opts.alignMessages,
+			fieldSeparator:  opts.fieldSeparator,
+			jsonValues:      opts.valueFormat == FieldValueJSON,
+			fieldKeyColor:   opts.fieldKeyColor,
+			fieldValueColor: opts.fieldValueColor,
+		},
+		utc:              opts.utc,
+		omitEmpty:        opts.omitEmpty,
+		fullFunctionName: opts.fullFunctionName,
+		callerPath:       opts.callerPath,
+		includeFunc:      opts.includeFunc,
+		includeFile:      opts.includeFile,
+		funcKey:          opts.funcKey,
+		fileKey:          opts.fileKey,
+	}
+}
+
+// validateCallerPathMode returns an error if mode is not one of the CallerPath* constants.
+func validateCallerPathMode(mode string) error {
+	switch mode {
+	case CallerPathBasename, CallerPathPackage, CallerPathFull:
+		return nil
+	default:
+		return fmt.Errorf("flogger: unknown caller path mode %q", mode)
+	}
+}
+
+// validateFieldValueFormat returns an error if format is not one of the FieldValue* constants.
+func validateFieldValueFormat(format string) error {
+	switch format {
+	case FieldValueDefault, FieldValueJSON:
+		return nil
+	default:
+		return fmt.Errorf("flogger: unknown field value format %q", format)
+	}
+}
+
+// callerFile renders file according to the caller path mode. Runtime file paths always use forward slashes.
+func callerFile(file, mode string) string {
+	switch mode {
+	case CallerPathFull:
+		return file
+	case CallerPathPackage:
+		dir, name := path.Split(file)
+		if dir == "" {
+			return name
+		}
+		return path.Base(dir) + "/" + name
+	default:
+		return path.Base(file)
+	}
+}
+
+// validateColor returns an error if color is not an ansi style such as "red", "red+b", "196" or "white+b:red".
+func validateColor(color string) error {
+	foregroundBackground := strings.Split(color, ":")
+	if len(foregroundBackground) > 2 {
+		return fmt.Errorf("flogger: unknown color %q", color)
+	}
+	for i, part := range foregroundBackground {
+		name, _, _ := strings.Cut(part, "+")
+		// The foreground may be omitted to only set attributes, e.g. "+b".
+		if name == "" && i == 0 {
+			continue
+		}
+		if _, ok := ansi.Colors[name]; !ok {
+			return fmt.Errorf("flogger: unknown color %q", color)
+		}
+	}
+	return nil
+}
+
+// formatterSwitch is the formatter installed on a Logger's logrus logger for its whole lifetime.
+// It delegates to the current customFormatter, which can be swapped atomically while other goroutines are formatting,
+// so reconfiguring never races with hooks that format entries outside of logrus' own lock.
+type formatterSwitch struct {
+	// current is the formatter built from the Logger's latest options.
+	current atomic.Pointer[customFormatter]
+	// transform post-processes every formatted line, nil to leave lines as they are.
+	transform atomic.Pointer[func([]byte) []byte]
+}
+
+// newFormatterSwitch creates a formatterSwitch delegating to a formatter built from opts.
+func newFormatterSwitch(opts formatterOptions) *formatterSwitch {
+	s := &formatterSwitch{}
+	s.current.Store(newFormatter(opts))
+	return s
+}
+
+// Format formats the entry with the current formatter and applies the line transform, if any.
+func (s *formatterSwitch) Format(entry *logrus.Entry) ([]byte, error) {
+	line, err := s.current.Load().Format(entry)
+	if err != nil {
+		return nil, err
+	}
+	if transform := s.transform.Load(); transform != nil {
+		line = (*transform)(line)
+	}
+	return line, nil
+}
+
+// Format is a method that overrides the default Format method of logrus.Entry.
+// It adds custom fields (function name and file location) to the log entry if the caller information is available.
+func (f *customFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	// The underlying formatter renders the caller itself.
+	if f.keepCaller {
+		clone := *entry
+		if f.utc {
+			clone.Time = clone.Time.UTC()
+		}
+		if f.omitEmpty {
+			clone.Data = withoutEmptyFields(clone.Data)
+		}
+		return f.formatter.Format(&clone)
+	}
+
+	// Check if the log entry has caller information (file and line number).
+	if entry.HasCaller() && (f.includeFunc || f.includeFile) {
+		// Initialize the log entry's data fields if they are nil.
+		if entry.Data == nil {
+			entry.Data = make(logrus.Fields)
+		}
+
+		// Stripped or optimized builds may only know part of the caller; the unknown parts are left out.
+		if f.includeFunc && knownCallerPart(entry.Caller.Function) {
+			// Extract the function name from the caller.
+			// The caller has already been moved to the user's call site by callerHook.
+			funcVal := entry.Caller.Function
+			// Trim the package path to keep the text readable, e.g. "svc.(*Server).Handle".
+			if !f.fullFunctionName {
+				funcVal = shortFunctionName(funcVal)
+			}
+			entry.Data[f.funcKey] = funcVal
+		}
+
+		if f.includeFile && knownCallerPart(entry.Caller.File) {
+			// Extract the file and line number from the caller and format it as "file:line", or just "file"
+			// if the line is unknown.
+			fileVal := callerFile(entry.Caller.File, f.callerPath)
+			if entry.Caller.Line > 0 {
+				fileVal = fmt.Sprintf("%s:%d", fileVal, entry.Caller.Line)
+			}
+			entry.Data[f.fileKey] = fileVal
+		}
+	}
+
+	// Hide the raw caller from the underlying formatter: the JSON formatter would otherwise add
+	// its own func/file keys and rename ours to "fields.func"/"fields.file".
+	clone := *entry
+	clone.Caller = nil
+
+	// Render the timestamp in UTC if requested.
+	if f.utc {
+		clone.Time = clone.Time.UTC()
+	}
+
+	if f.extractKV {
+		clone.Message, clone.Data = extractMessageFields(clone.Message, clone.Data)
+	}
+
+	if f.omitEmpty {
+		clone.Data = withoutEmptyFields(clone.Data)
+	}
+
+	// The JSON formatter only knows logrus levels, so a registered level's name replaces it afterwards.
+	if name, ok := levelName(&clone); ok && f.jsonLevelKey != "" {
+		clone.Data = withoutLevelName(clone.Data)
+		out, err := f.formatter.Format(&clone)
+		if err != nil {
+			return nil, err
+		}
+		return renameJSONLevel(out, f.jsonLevelKey, name)
+	}
+
+	// Use the underlying formatter to format the log entry.
+	return f.formatter.Format(&clone)
+}
+
+// withoutEmptyFields returns data without the fields whose value is nil, an empty string or an empty slice or map,
+// copying it only if there are any.
+func withoutEmptyFields(data logrus.Fields) logrus.Fields {
+	empty := 0
+	for _, v := range data {
+		if isEmptyValue(v) {
+			empty++
+		}
+	}
+	if empty == 0 {
+		return data
+	}
+
+	fields := make(logrus.Fields, len(data)-empty)
+	for k, v := range data {
+		if !isEmptyValue(v) {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+// isEmptyValue reports whether v is nil, a nil pointer or interface, an empty string or an empty slice or map.
+func isEmptyValue(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case int, int64, float64, bool, time.Duration, time.Time:
+		// The common scalar types are never empty, skip reflection for them.
+		return false
+	}
+
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return value.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
+
+// knownCallerPart reports whether a caller's function or file is known: the runtime reports unknown ones as
+// empty or "???".
+func knownCallerPart(s string) bool {
+	return s != "" && s != "???"
+}
+
+// shortFunctionName trims a fully qualified function name to its last package component, e.g.
+// "github.com/me/app/internal/svc.(*Server).Handle" becomes "svc.(*Server).Handle".
+func shortFunctionName(function string) string {
+	// Package paths cannot contain a slash after the package name, so the last slash ends the path.
+	if lastSlash := strings.LastIndex(function, "/"); lastSlash >= 0 {
+		return function[lastSlash+1:]
+	}
+	return function
+}
@@ -0,0 +1,101 @@
+package flogger
+
+import (
+	"github.com/sirupsen/logrus"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestShortFunctionName(t *testing.T) {
+	tests := []struct {
+		function string
+		want     string
+	}{
+		{"github.com/me/app/internal/svc.(*Server).Handle", "svc.(*Server).Handle"},
+		{"github.com/me/app/svc.Handle.func1", "svc.Handle.func1"},
+		{"github.com/me/app/svc.Server.Handle", "svc.Server.Handle"},
+		{"main.main", "main.main"},
+		{"net/http.HandlerFunc.ServeHTTP", "http.HandlerFunc.ServeHTTP"},
+		{"gopkg.in/yaml.v3.Unmarshal", "yaml.v3.Unmarshal"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := shortFunctionName(tt.function); got != tt.want {
+			t.Errorf("shortFunctionName(%q) = %q, want %q", tt.function, got, tt.want)
+		}
+	}
+}
+
+// callerEntry returns an entry logged from the given caller, as logrus records it.
+func callerEntry(caller *runtime.Frame) *logrus.Entry {
+	log := logrus.New()
+	log.SetReportCaller(true)
+	entry := logrus.NewEntry(log)
+	entry.Level = logrus.InfoLevel
+	entry.Time = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	entry.Message = "msg"
+	entry.Caller = caller
+	return entry
+}
+
+func TestFormatterFunctionName(t *testing.T) {
+	caller := &runtime.Frame{Function: "github.com/me/app/svc.(*Server).Handle", File: "/src/app/svc/handler.go", Line: 42}
+
+	for _, tt := range []struct {
+		full bool
+		want string
+	}{
+		{false, "svc.(*Server).Handle"},
+		{true, "github.com/me/app/svc.(*Server).Handle"},
+	} {
+		opts := defaultFormatterOptions()
+		opts.format = FormatJSON
+		opts.fullFunctionName = tt.full
+
+		out, err := newFormatter(opts).Format(callerEntry(caller))
+		if err != nil {
+			t.Fatal(err)
+		}
+		fields := decodeJSON(t, out)
+		if fields[funcField] != tt.want {
+			t.Errorf("full=%v: func = %v, want %s", tt.full, fields[funcField], tt.want)
+		}
+		if fields[fileField] != "handler.go:42" {
+			t.Errorf("full=%v: file = %v, want handler.go:42", tt.full, fields[fileField])
+		}
+	}
+}
+
+func TestFormatterPartialCaller(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		caller   runtime.Frame
+		wantFunc interface{}
+		wantFile interface{}
+	}{
+		{"no function", runtime.Frame{File: "/src/app/handler.go", Line: 42}, nil, "handler.go:42"},
+		{"unknown function", runtime.Frame{Function: "???", File: "/src/app/handler.go", Line: 42}, nil, "handler.go:42"},
+		{"unknown file", runtime.Frame{Function: "app.Handle", File: "???"}, "app.Handle", nil},
+		{"no line", runtime.Frame{Function: "app.Handle", File: "/src/app/handler.go"}, "app.Handle", "handler.go"},
+		{"nothing known", runtime.Frame{Function: "???", File: "???"}, nil, nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := defaultFormatterOptions()
+			opts.format = FormatJSON
+
+			caller := tt.caller
+			out, err := newFormatter(opts).Format(callerEntry(&caller))
+			if err != nil {
+				t.Fatal(err)
+			}
+			fields := decodeJSON(t, out)
+			if fields[funcField] != tt.wantFunc {
+				t.Errorf("func = %v, want %v", fields[funcField], tt.wantFunc)
+			}
+			if fields[fileField] != tt.wantFile {
+				t.Errorf("file = %v, want %v", fields[fileField], tt.wantFile)
+			}
+		})
+	}
+}
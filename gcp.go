@@ -0,0 +1,111 @@
+package flogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// keys of the Google Cloud Logging structured log format.
+const (
+	// gcpSeverityKey holds the severity of the entry.
+	gcpSeverityKey = "severity"
+	// gcpMessageKey holds the message.
+	gcpMessageKey = "message"
+	// gcpTimeKey holds the time of the entry.
+	gcpTimeKey = "time"
+	// gcpSourceLocationKey holds the caller of the entry.
+	gcpSourceLocationKey = "logging.googleapis.com/sourceLocation"
+)
+
+// gcpSourceLocation is the caller of an entry in the shape Cloud Logging expects.
+type gcpSourceLocation struct {
+	// File is the full path of the source file.
+	File string `json:"file,omitempty"`
+	// Line is the line number, as a string like Cloud Logging's own LogEntrySourceLocation.
+	Line string `json:"line,omitempty"`
+	// Function is the fully qualified function name.
+	Function string `json:"function,omitempty"`
+}
+
+// gcpFormatter renders entries as JSON understood by Google Cloud Logging, e.g. on Cloud Run and GKE:
+// the level as severity, the message as message, and the caller as a sourceLocation object.
+type gcpFormatter struct {
+	// includeSourceLocation adds the caller, if the entry has one.
+	includeSourceLocation bool
+}
+
+// Format renders the entry as one JSON object per line.
+func (f *gcpFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	data := make(map[string]interface{}, len(entry.Data)+4)
+	for k, v := range withoutLevelName(entry.Data) {
+		// Like the JSON formatter, keep fields clashing with the keys of the format under a "fields." prefix.
+		switch k {
+		case gcpSeverityKey, gcpMessageKey, gcpTimeKey, gcpSourceLocationKey:
+			k = "fields." + k
+		}
+		// Errors have no exported fields, log their message instead.
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		data[k] = v
+	}
+
+	data[gcpSeverityKey] = gcpSeverity(entry.Level)
+	// A registered level named like a Cloud Logging severity, such as notice, maps to that severity.
+	if name, ok := levelName(entry); ok && gcpSeverities[strings.ToUpper(name)] {
+		data[gcpSeverityKey] = strings.ToUpper(name)
+	}
+	data[gcpMessageKey] = entry.Message
+	data[gcpTimeKey] = entry.Time.Format(time.RFC3339Nano)
+	if f.includeSourceLocation && entry.HasCaller() {
+		// Leave out the parts of the caller a stripped build does not know, and the whole location if none is known.
+		var location gcpSourceLocation
+		if knownCallerPart(entry.Caller.File) {
+			location.File = entry.Caller.File
+		}
+		if entry.Caller.Line > 0 {
+			location.Line = strconv.Itoa(entry.Caller.Line)
+		}
+		if knownCallerPart(entry.Caller.Function) {
+			location.Function = entry.Caller.Function
+		}
+		if location != (gcpSourceLocation{}) {
+			data[gcpSourceLocationKey] = location
+		}
+	}
+
+	b := &bytes.Buffer{}
+	if err := json.NewEncoder(b).Encode(data); err != nil {
+		return nil, fmt.Errorf("flogger: marshal entry to JSON: %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+// gcpSeverities holds the severities known to Cloud Logging.
+var gcpSeverities = map[string]bool{
+	"DEFAULT": true, "DEBUG": true, "INFO": true, "NOTICE": true, "WARNING": true,
+	"ERROR": true, "CRITICAL": true, "ALERT": true, "EMERGENCY": true,
+}
+
+// gcpSeverity maps a logrus level to its Cloud Logging severity.
+func gcpSeverity(level logrus.Level) string {
+	switch level {
+	case logrus.PanicLevel:
+		return "ALERT"
+	case logrus.FatalLevel:
+		return "CRITICAL"
+	case logrus.ErrorLevel:
+		return "ERROR"
+	case logrus.WarnLevel:
+		return "WARNING"
+	case logrus.InfoLevel:
+		return "INFO"
+	default:
+		return "DEBUG"
+	}
+}
@@ -0,0 +1,75 @@
+package flogger
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestGCPFormat(t *testing.T) {
+	caller := &runtime.Frame{Function: "github.com/me/app/svc.(*Server).Handle", File: "/src/app/svc/handler.go", Line: 42}
+	entry := callerEntry(caller)
+	entry.Data["user"] = "bob"
+	// Fields clashing with the keys of the format keep a "fields." prefix.
+	entry.Data["severity"] = "high"
+
+	opts := defaultFormatterOptions()
+	opts.format = FormatGCP
+	out, err := newFormatter(opts).Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"fields.severity":"high",` +
+		`"logging.googleapis.com/sourceLocation":{"file":"/src/app/svc/handler.go","line":"42","function":"github.com/me/app/svc.(*Server).Handle"},` +
+		`"message":"msg","severity":"INFO","time":"2024-01-01T12:00:00Z","user":"bob"}` + "\n"
+	if string(out) != want {
+		t.Errorf("got  %s\nwant %s", out, want)
+	}
+}
+
+func TestGCPFormatWithoutCaller(t *testing.T) {
+	caller := &runtime.Frame{Function: "github.com/me/app/svc.(*Server).Handle", File: "/src/app/svc/handler.go", Line: 42}
+
+	opts := defaultFormatterOptions()
+	opts.format = FormatGCP
+	opts.includeFunc = false
+	opts.includeFile = false
+	out, err := newFormatter(opts).Format(callerEntry(caller))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"message":"msg","severity":"INFO","time":"2024-01-01T12:00:00Z"}` + "\n"
+	if string(out) != want {
+		t.Errorf("got  %s\nwant %s", out, want)
+	}
+}
+
+func TestGCPFormatPartialCaller(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		caller runtime.Frame
+		want   string
+	}{
+		{"unknown function", runtime.Frame{Function: "???", File: "/src/app/handler.go", Line: 42},
+			`"logging.googleapis.com/sourceLocation":{"file":"/src/app/handler.go","line":"42"},`},
+		{"unknown file", runtime.Frame{Function: "app.Handle", File: "???"},
+			`"logging.googleapis.com/sourceLocation":{"function":"app.Handle"},`},
+		{"nothing known", runtime.Frame{Function: "???", File: "???"}, ``},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := defaultFormatterOptions()
+			opts.format = FormatGCP
+
+			caller := tt.caller
+			out, err := newFormatter(opts).Format(callerEntry(&caller))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := `{` + tt.want + `"message":"msg","severity":"INFO","time":"2024-01-01T12:00:00Z"}` + "\n"
+			if string(out) != want {
+				t.Errorf("got  %s\nwant %s", out, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,482 @@
+package flogger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// duplicate field policies accepted by SetDuplicateFieldPolicy, deciding what happens when a default field and
+// a field set on the entry share a key.
+const (
+	// DuplicateFieldOverride keeps the entry's own field and drops the default (the default).
+	DuplicateFieldOverride = "override"
+	// DuplicateFieldKeep replaces the entry's own field with the default.
+	DuplicateFieldKeep = "keep"
+	// DuplicateFieldRename keeps both: the entry's own field under the key and the default under the key
+	// followed by "#2", or the next free number.
+	DuplicateFieldRename = "rename"
+)
+
+// defaultFieldsHook merges a set of default fields into every entry.
+// Fields set on the entry itself take precedence unless the duplicate field policy says otherwise,
+// so WithFields can override a default per call.
+type defaultFieldsHook struct {
+	// mu guards fields and policy, which may be replaced while other goroutines are logging.
+	mu sync.RWMutex
+	// fields holds the default fields added to every entry.
+	fields logrus.Fields
+	// policy resolves a default field colliding with one of the entry's, one of the DuplicateField* constants;
+	// empty means DuplicateFieldOverride.
+	policy string
+}
+
+// Levels returns all levels, since default fields apply regardless of level.
+func (h *defaultFieldsHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds every default field that is not already set on the entry, and resolves the others by the policy.
+func (h *defaultFieldsHook) Fire(entry *logrus.Entry) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	// Nothing to merge, keep the hot path cheap.
+	if len(h.fields) == 0 {
+		return nil
+	}
+
+	if entry.Data == nil {
+		entry.Data = make(logrus.Fields, len(h.fields))
+	}
+	for k, v := range h.fields {
+		if _, ok := entry.Data[k]; !ok {
+			entry.Data[k] = v
+			continue
+		}
+		switch h.policy {
+		case DuplicateFieldKeep:
+			entry.Data[k] = v
+		case DuplicateFieldRename:
+			entry.Data[renamedKey(entry.Data, k)] = v
+		}
+	}
+	return nil
+}
+
+// renamedKey returns key followed by "#2", or the first "#n" without a field in data.
+func renamedKey(data logrus.Fields, key string) string {
+	for n := 2; ; n++ {
+		renamed := key + "#" + strconv.Itoa(n)
+		if _, ok := data[renamed]; !ok {
+			return renamed
+		}
+	}
+}
+
+// validateDuplicateFieldPolicy returns an error if policy is not one of the DuplicateField* constants.
+func validateDuplicateFieldPolicy(policy string) error {
+	switch policy {
+	case DuplicateFieldOverride, DuplicateFieldKeep, DuplicateFieldRename:
+		return nil
+	default:
+		return fmt.Errorf("flogger: unknown duplicate field policy %q", policy)
+	}
+}
+
+// setPolicy sets the duplicate field policy.
+func (h *defaultFieldsHook) setPolicy(policy string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.policy = policy
+}
+
+// set replaces the default fields with a copy of fields.
+func (h *defaultFieldsHook) set(fields map[string]interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.fields = make(logrus.Fields, len(fields))
+	for k, v := range fields {
+		h.fields[k] = v
+	}
+}
+
+// get returns a copy of the default fields.
+func (h *defaultFieldsHook) get() map[string]interface{} {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	fields := make(map[string]interface{}, len(h.fields))
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	return fields
+}
+
+// add sets a single default field, keeping the others.
+func (h *defaultFieldsHook) add(key string, value interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.fields == nil {
+		h.fields = make(logrus.Fields)
+	}
+	h.fields[key] = value
+}
+
+// FieldProviderPanicField is the field a field provider's panic value is logged under, instead of its fields.
+const FieldProviderPanicField = "field_provider_panic"
+
+// fieldProviderHook merges the fields returned by the providers registered with AddFieldProvider into every entry.
+// Like default fields, they never override the fields set on the entry itself.
+type fieldProviderHook struct {
+	// mu guards providers.
+	mu sync.RWMutex
+	// providers holds the registered providers in registration order.
+	providers []func() map[string]interface{}
+}
+
+// Levels returns all levels, since provided fields apply regardless of level.
+func (h *fieldProviderHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire runs every provider in registration order, later ones overriding the keys of earlier ones,
+// and adds the resulting fields that are not already set on the entry.
+func (h *fieldProviderHook) Fire(entry *logrus.Entry) error {
+	h.mu.RLock()
+	providers := h.providers
+	h.mu.RUnlock()
+
+	// Nothing registered, keep the hot path cheap.
+	if len(providers) == 0 {
+		return nil
+	}
+
+	provided := make(logrus.Fields)
+	for _, provider := range providers {
+		if r := runFieldProvider(provider, provided); r != nil {
+			provided[FieldProviderPanicField] = r
+		}
+	}
+
+	if entry.Data == nil {
+		entry.Data = make(logrus.Fields, len(provided))
+	}
+	for k, v := range provided {
+		if _, ok := entry.Data[k]; !ok {
+			entry.Data[k] = v
+		}
+	}
+	return nil
+}
+
+// runFieldProvider merges the fields returned by provider into fields, returning the value it panicked with, if any,
+// so one bad provider cannot break logging.
+func runFieldProvider(provider func() map[string]interface{}, fields logrus.Fields) (panicked interface{}) {
+	defer func() {
+		panicked = recover()
+	}()
+
+	for k, v := range provider() {
+		fields[k] = v
+	}
+	return nil
+}
+
+// add registers a provider after the existing ones.
+func (h *fieldProviderHook) add(provider func() map[string]interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Copy the slice, Fire may still be iterating the previous one.
+	h.providers = append(h.providers[:len(h.providers):len(h.providers)], provider)
+}
+
+// GoroutineField is the field under which SetReportGoroutineID logs the ID of the logging goroutine.
+const GoroutineField = "goroutine"
+
+// clockHook replaces the time of every entry with the time returned by the clock set with SetClock.
+type clockHook struct {
+	// now returns the current time, nil to keep the time logrus assigned.
+	now atomic.Pointer[func() time.Time]
+}
+
+// Levels returns all levels, since every entry carries a timestamp.
+func (h *clockHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire sets the entry's time from the clock, if one is set.
+func (h *clockHook) Fire(entry *logrus.Entry) error {
+	if now := h.now.Load(); now != nil {
+		entry.Time = (*now)()
+	}
+	return nil
+}
+
+// goroutineHook adds the ID of the logging goroutine to every entry while enabled.
+type goroutineHook struct {
+	// enabled turns the hook on; it is off by default because parsing the stack costs time on every call.
+	enabled atomic.Bool
+}
+
+// Levels returns all levels, since the goroutine ID applies regardless of level.
+func (h *goroutineHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds the goroutine ID if enabled. Hooks run on the logging goroutine, so the ID is the caller's.
+func (h *goroutineHook) Fire(entry *logrus.Entry) error {
+	if !h.enabled.Load() {
+		return nil
+	}
+	if id, ok := goroutineID(); ok {
+		entry.Data[GoroutineField] = id
+	}
+	return nil
+}
+
+// goroutineID returns the ID of the current goroutine, parsed from the first line of its stack
+// ("goroutine 123 [running]:"), as the runtime offers no cheaper way to obtain it.
+func goroutineID() (uint64, bool) {
+	var buf [64]byte
+	line := buf[:runtime.Stack(buf[:], false)]
+	line = bytes.TrimPrefix(line, []byte("goroutine "))
+	if i := bytes.IndexByte(line, ' '); i >= 0 {
+		line = line[:i]
+	}
+	id, err := strconv.ParseUint(string(line), 10, 64)
+	return id, err == nil
+}
+
+// redactedValue replaces the value of every redacted field.
+const redactedValue = "***"
+
+// redactHook masks the values of fields whose keys were registered as sensitive.
+type redactHook struct {
+	// mu guards keys.
+	mu sync.RWMutex
+	// keys holds the lower-cased keys to redact.
+	keys map[string]struct{}
+}
+
+// Levels returns all levels, since secrets must not leak at any level.
+func (h *redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire replaces the value of every field with a registered key (case-insensitive) by redactedValue.
+func (h *redactHook) Fire(entry *logrus.Entry) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	// Nothing registered, keep the hot path cheap.
+	if len(h.keys) == 0 {
+		return nil
+	}
+
+	for k := range entry.Data {
+		if _, ok := h.keys[strings.ToLower(k)]; ok {
+			entry.Data[k] = redactedValue
+		}
+	}
+	return nil
+}
+
+// add registers additional keys to redact.
+func (h *redactHook) add(keys ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.keys == nil {
+		h.keys = make(map[string]struct{}, len(keys))
+	}
+	for _, k := range keys {
+		h.keys[strings.ToLower(k)] = struct{}{}
+	}
+}
+
+// truncatedSuffix marks a field value or message cut by truncateHook.
+const truncatedSuffix = "...(truncated)"
+
+// truncateHook caps the length of field values and of the message.
+type truncateHook struct {
+	// maxField is the maximum length of a rendered field value in characters, zero for no limit.
+	maxField atomic.Int64
+	// maxMessage is the maximum length of the message in characters, zero for no limit.
+	maxMessage atomic.Int64
+}
+
+// Levels returns all levels, since oversized entries are a problem at any level.
+func (h *truncateHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire replaces every field value rendering longer than the limit by its truncated rendering, and truncates the message.
+func (h *truncateHook) Fire(entry *logrus.Entry) error {
+	if n := int(h.maxMessage.Load()); n > 0 {
+		if truncated, ok := truncate(entry.Message, n); ok {
+			entry.Message = truncated
+		}
+	}
+
+	n := int(h.maxField.Load())
+	if n <= 0 {
+		return nil
+	}
+	for k, v := range entry.Data {
+		// Strings are checked as they are, other values as the text formatter would render them.
+		s, ok := v.(string)
+		if !ok {
+			s = fmt.Sprint(v)
+		}
+		if truncated, ok := truncate(s, n); ok {
+			entry.Data[k] = truncated
+		}
+	}
+	return nil
+}
+
+// truncate cuts s to n characters followed by truncatedSuffix, reporting whether s was longer than n characters.
+func truncate(s string, n int) (string, bool) {
+	// A string with at most n bytes cannot have more than n characters.
+	if len(s) <= n || utf8.RuneCountInString(s) <= n {
+		return s, false
+	}
+	runes := 0
+	for i := range s {
+		if runes == n {
+			return s[:i] + truncatedSuffix, true
+		}
+		runes++
+	}
+	return s, false
+}
+
+// entryOutputHook writes every entry carrying writers added with WithOutput to them, in addition to the outputs.
+// The writers travel in the entry's context rather than its fields, so no hook or formatter treats them as a field.
+type entryOutputHook struct {
+	// formatter formats the entries, shared with the Logger.
+	formatter logrus.Formatter
+}
+
+// Levels returns all levels, the writers are decided per entry.
+func (h *entryOutputHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire formats the entry and writes it to the writers added with WithOutput, if any.
+// Failing writers do not affect the outputs, so their errors are ignored.
+func (h *entryOutputHook) Fire(entry *logrus.Entry) error {
+	writers := entryOutputs(entry.Context)
+	if len(writers) == 0 {
+		return nil
+	}
+
+	serialized, err := h.formatter.Format(entry)
+	if err != nil {
+		return nil
+	}
+	for _, w := range writers {
+		_, _ = w.Write(serialized)
+	}
+	return nil
+}
+
+// entryOutputs returns the writers WithOutput stored in ctx, nil if there are none or ctx is nil.
+func entryOutputs(ctx context.Context) []io.Writer {
+	if ctx == nil {
+		return nil
+	}
+	writers, _ := ctx.Value(outputsKey).([]io.Writer)
+	return writers
+}
+
+// withEntryOutputs returns a copy of ctx carrying writers, with context.Background standing in for a nil ctx.
+func withEntryOutputs(ctx context.Context, writers []io.Writer) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, outputsKey, writers)
+}
+
+// levelOutputHook writes every entry to the writer configured for its level, which is how SplitOutput routes entries.
+// It is inactive until writers are configured.
+type levelOutputHook struct {
+	// formatter formats the routed entries, shared with the Logger.
+	formatter logrus.Formatter
+
+	// mu guards writers and serializes the writes.
+	mu sync.Mutex
+	// writers maps each routed level to its writer.
+	writers map[logrus.Level]io.Writer
+	// fallback receives the entries a routed writer failed to write, nil to report the failure instead.
+	fallback io.Writer
+}
+
+// Levels returns all levels, the routing itself is decided per entry.
+func (h *levelOutputHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire formats the entry and writes it to the writer configured for its level, if any.
+func (h *levelOutputHook) Fire(entry *logrus.Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	w, ok := h.writers[entry.Level]
+	if !ok {
+		return nil
+	}
+	serialized, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(serialized); err != nil && h.fallback != nil {
+		_, err = h.fallback.Write(serialized)
+	}
+	return err
+}
+
+// setFallback sets the writer receiving the entries a routed writer failed to write; nil removes it.
+func (h *levelOutputHook) setFallback(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.fallback = w
+}
+
+// set replaces the level routing; a nil map disables it.
+func (h *levelOutputHook) set(writers map[logrus.Level]io.Writer) {
+	h.swap(writers)
+}
+
+// get returns the level routing, nil if it is disabled. The map is never modified once set, so it is not copied.
+func (h *levelOutputHook) get() map[logrus.Level]io.Writer {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.writers
+}
+
+// swap replaces the level routing and returns the previous one.
+func (h *levelOutputHook) swap(writers map[logrus.Level]io.Writer) map[logrus.Level]io.Writer {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	previous := h.writers
+	h.writers = writers
+	return previous
+}
@@ -0,0 +1,65 @@
+package flogger
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRedactKeysText(t *testing.T) {
+	l, buf := newTestLogger()
+	l.RedactKeys("password")
+	l.SetDefaultFields(map[string]interface{}{"Password": "default-secret"})
+
+	l.WithField("password", "hunter2").WithField("user", "bob").Info("login")
+	l.Info("default")
+
+	out := buf.String()
+	if strings.Contains(out, "hunter2") || strings.Contains(out, "default-secret") {
+		t.Errorf("output %q leaks a password", out)
+	}
+	if !strings.Contains(out, "password=*** user=bob") {
+		t.Errorf("output %q lacks the redacted per-call field", out)
+	}
+	if !strings.Contains(out, "default Password=***") {
+		t.Errorf("output %q lacks the redacted default field", out)
+	}
+}
+
+func TestRedactKeysJSON(t *testing.T) {
+	l, buf := newJSONLogger(t)
+	l.RedactKeys("PASSWORD", "token")
+
+	l.WithFields(map[string]interface{}{"password": "hunter2", "token": 42, "user": "bob"}).Info("login")
+
+	fields := decodeJSON(t, buf.Bytes())
+	for _, key := range []string{"password", "token"} {
+		if fields[key] != redactedValue {
+			t.Errorf("%s = %v, want %s", key, fields[key], redactedValue)
+		}
+	}
+	if fields["user"] != "bob" {
+		t.Errorf("user = %v, want it unredacted", fields["user"])
+	}
+}
+
+// benchmarkGoroutineID measures Info calls writing to io.Discard with goroutine ID reporting on or off.
+func benchmarkGoroutineID(b *testing.B, enabled bool) {
+	l := New()
+	l.SetOutput(io.Discard)
+	l.SetReportGoroutineID(enabled)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("request %d done", i)
+	}
+}
+
+// BenchmarkGoroutineIDOff is the baseline of BenchmarkGoroutineIDOn.
+func BenchmarkGoroutineIDOff(b *testing.B) {
+	benchmarkGoroutineID(b, false)
+}
+
+// BenchmarkGoroutineIDOn measures the overhead of parsing the goroutine's stack for its ID on every call.
+func BenchmarkGoroutineIDOn(b *testing.B) {
+	benchmarkGoroutineID(b, true)
+}
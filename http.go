@@ -0,0 +1,106 @@
+package flogger
+
+import (
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"time"
+)
+
+// fields added by the HTTP middleware to the entry of every request.
+const (
+	// httpMethodField holds the request method.
+	httpMethodField = "method"
+	// httpPathField holds the request path.
+	httpPathField = "path"
+	// httpStatusField holds the response status code.
+	httpStatusField = "status"
+	// httpDurationField holds the time spent serving the request.
+	httpDurationField = "duration"
+)
+
+// HTTPMiddleware returns a handler that serves requests with next and logs each one with its method, path,
+// status code and duration as fields: at the Info level, or at the Error level for 5xx responses and for handlers
+// that panic, which are logged with a 500 status before the panic continues.
+// The request's context carries a Child logger with the method and path fields, see FromContext.
+func (l *Logger) HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		reqLog := l.Child(map[string]interface{}{
+			httpMethodField: r.Method,
+			httpPathField:   r.URL.Path,
+		})
+		r = r.WithContext(IntoContext(r.Context(), reqLog))
+
+		rec := &statusRecorder{ResponseWriter: w}
+		// Log from a defer so requests whose handler panics are logged too, as failed with a 500 status.
+		defer func() {
+			recovered := recover()
+			status := rec.statusCode()
+			if recovered != nil {
+				status = http.StatusInternalServerError
+			}
+
+			level := logrus.InfoLevel
+			if status >= http.StatusInternalServerError {
+				level = logrus.ErrorLevel
+			}
+			entry := reqLog.newEntry().WithContext(r.Context()).WithFields(logrus.Fields{
+				httpStatusField:   status,
+				httpDurationField: time.Since(start),
+			})
+			reqLog.logf(entry, level, "%s %s", []interface{}{r.Method, r.URL.Path})
+
+			// Leave the panic to net/http or an outer recovery middleware.
+			if recovered != nil {
+				panic(recovered)
+			}
+		}()
+		next.ServeHTTP(rec, r)
+	})
+}
+
+// statusRecorder is an http.ResponseWriter remembering the status code written through it.
+type statusRecorder struct {
+	http.ResponseWriter
+	// status is the status code written, zero until the header is written.
+	status int
+}
+
+// WriteHeader records the status code and writes it.
+func (w *statusRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write writes b, recording the implicit 200 status code if no header was written yet.
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the wrapped writer, so http.ResponseController can reach its optional interfaces.
+func (w *statusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Flush flushes the wrapped writer if it supports flushing, e.g. for streamed responses.
+func (w *statusRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+// statusCode returns the written status code, 200 if the handler wrote nothing, as net/http does.
+func (w *statusRecorder) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
@@ -0,0 +1,63 @@
+package flogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// serve runs one GET /orders request through l's middleware around handler and returns the logged entry.
+func serve(t *testing.T, handler http.HandlerFunc) map[string]interface{} {
+	t.Helper()
+	l, buf := newJSONLogger(t)
+
+	func() {
+		// The recorder does not recover panics like net/http's server does.
+		defer func() { _ = recover() }()
+		l.HTTPMiddleware(handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+	}()
+	return decodeJSON(t, buf.Bytes())
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantLevel  string
+		wantStatus float64
+	}{
+		{"implicit OK", func(w http.ResponseWriter, r *http.Request) {}, "info", http.StatusOK},
+		{"not found", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) }, "info", http.StatusNotFound},
+		{"server error", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusBadGateway) }, "error", http.StatusBadGateway},
+		{"panic", func(w http.ResponseWriter, r *http.Request) { panic("boom") }, "error", http.StatusInternalServerError},
+		{"panic after header", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			panic("boom")
+		}, "error", http.StatusInternalServerError},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := serve(t, tt.handler)
+			if fields["level"] != tt.wantLevel {
+				t.Errorf("level = %v, want %s", fields["level"], tt.wantLevel)
+			}
+			if fields[httpStatusField] != tt.wantStatus {
+				t.Errorf("status = %v, want %v", fields[httpStatusField], tt.wantStatus)
+			}
+			if fields[httpMethodField] != http.MethodGet || fields[httpPathField] != "/orders" {
+				t.Errorf("method and path = %v %v, want GET /orders", fields[httpMethodField], fields[httpPathField])
+			}
+		})
+	}
+}
+
+func TestHTTPMiddlewareRepanics(t *testing.T) {
+	l, _ := newJSONLogger(t)
+	handler := l.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { panic("boom") }))
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("recovered %v, want the handler's panic", r)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
@@ -0,0 +1,30 @@
+package flogger
+
+// Interface is the leveled logging API of Logger and Entry, for code that accepts a logger as a dependency so
+// tests can substitute a fake recording the messages, see the example.
+type Interface interface {
+	// Debug logs a message at the Debug level with formatting.
+	Debug(format string, args ...interface{})
+	// Info logs a message at the Info level with formatting.
+	Info(format string, args ...interface{})
+	// Warn logs a message at the Warn level with formatting.
+	Warn(format string, args ...interface{})
+	// Error logs a message at the Error level with formatting.
+	Error(format string, args ...interface{})
+}
+
+// Both loggers and entries can be injected.
+var (
+	_ Interface = (*Logger)(nil)
+	_ Interface = (*Entry)(nil)
+)
+
+// NewInterface creates a Logger like New and returns it as an Interface.
+func NewInterface() Interface {
+	return New()
+}
+
+// Default returns the package-level logger as an Interface, to inject it where a logger is expected.
+func Default() Interface {
+	return std()
+}
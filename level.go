@@ -0,0 +1,149 @@
+package flogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"sync"
+)
+
+// NoticeLevel is the name of the pseudo-level Notice logs at, registered below Warn by default.
+const NoticeLevel = "notice"
+
+// LevelNameField carries the name of a registered level on its entries, for the formatters to render
+// in place of the logrus level. The formatters never render it as a field, and hooks can read it with LevelName.
+const LevelNameField = "flogger.level"
+
+// customLevels maps the names of the levels registered with RegisterLevel to the logrus level their entries
+// are filtered and routed as.
+type customLevels struct {
+	// mu guards levels.
+	mu sync.RWMutex
+	// levels maps lower-cased level names to their logrus level.
+	levels map[string]logrus.Level
+}
+
+// get returns the logrus level of the registered level name.
+func (c *customLevels) get(name string) (logrus.Level, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	level, ok := c.levels[strings.ToLower(name)]
+	return level, ok
+}
+
+// set registers name as a pseudo-level of the given logrus level.
+func (c *customLevels) set(name string, level logrus.Level) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.levels == nil {
+		c.levels = make(map[string]logrus.Level)
+	}
+	c.levels[strings.ToLower(name)] = level
+}
+
+// RegisterLevel registers a pseudo-level named name that ranks just below the level below, e.g. the default
+// RegisterLevel("notice", "warn") for Notice. Its entries are filtered, routed and hooked as the next more verbose
+// logrus level (Info for notice), but rendered with the name as their level, in the color set with SetLevelColor.
+// It returns an error if name is empty or a logrus level, or if below is Panic or Trace.
+func (l *Logger) RegisterLevel(name, below string) error {
+	if name == "" {
+		return fmt.Errorf("flogger: empty level name")
+	}
+	if _, err := logrus.ParseLevel(name); err == nil {
+		return fmt.Errorf("flogger: level %q already exists", name)
+	}
+	belowLevel, err := logrus.ParseLevel(below)
+	if err != nil {
+		return err
+	}
+	// Nothing is less severe than Trace, and a level ranking below Panic would exit the process like Fatal.
+	if belowLevel == logrus.PanicLevel || belowLevel == logrus.TraceLevel {
+		return fmt.Errorf("flogger: cannot register a level below %q", below)
+	}
+
+	l.customLevels.set(name, belowLevel+1)
+	return nil
+}
+
+// Notice logs a message at the notice pseudo-level with formatting: it is filtered as Info,
+// but rendered as NOTICE, e.g. for audit events that need a distinct tag.
+func (l *Logger) Notice(format string, args ...interface{}) {
+	l.logNamed(nil, NoticeLevel, format, args)
+}
+
+// Notice logs a message at the notice pseudo-level with formatting, including the entry's fields, see Logger.Notice.
+func (e *Entry) Notice(format string, args ...interface{}) {
+	e.logger.logNamed(e.entry, NoticeLevel, format, args)
+}
+
+// logNamed logs a message at the registered level name with formatting. Unknown names are logged at Info.
+func (l *Logger) logNamed(entry *logrus.Entry, name, format string, args []interface{}) {
+	level, ok := l.customLevels.get(name)
+	if !ok {
+		level = logrus.InfoLevel
+	}
+	if !l.enabled(level) {
+		return
+	}
+	if entry == nil {
+		entry = l.newEntry()
+	}
+
+	msg := format
+	if len(args) > 0 {
+		msg = fmt.Sprintf(format, args...)
+	}
+	l.writeKeyed(entry.WithField(LevelNameField, strings.ToLower(name)), level, msg, format)
+}
+
+// levelName returns the registered level name an entry was logged at, if any.
+func levelName(entry *logrus.Entry) (string, bool) {
+	name, ok := entry.Data[LevelNameField].(string)
+	return name, ok
+}
+
+// LevelName returns the name of the level entry was logged at, for use in hooks: the name of the level registered
+// with RegisterLevel it was logged at, e.g. "notice", or else the name of its logrus level.
+func LevelName(entry *logrus.Entry) string {
+	if name, ok := levelName(entry); ok {
+		return name
+	}
+	return entry.Level.String()
+}
+
+// withoutLevelName returns the entry's fields without the registered level name, copying them only if needed.
+func withoutLevelName(data logrus.Fields) logrus.Fields {
+	if _, ok := data[LevelNameField]; !ok {
+		return data
+	}
+	fields := make(logrus.Fields, len(data)-1)
+	for k, v := range data {
+		if k != LevelNameField {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+// renameJSONLevel replaces the value of the key holding the level in the JSON object line with name.
+func renameJSONLevel(line []byte, key, name string) ([]byte, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(line, &object); err != nil {
+		return nil, fmt.Errorf("flogger: rename level in JSON: %w", err)
+	}
+	value, err := json.Marshal(name)
+	if err != nil {
+		return nil, fmt.Errorf("flogger: rename level in JSON: %w", err)
+	}
+	object[key] = value
+
+	b := &bytes.Buffer{}
+	if err := json.NewEncoder(b).Encode(object); err != nil {
+		return nil, fmt.Errorf("flogger: rename level in JSON: %w", err)
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,42 @@
+package flogger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsLevelEnabledRegisteredLevels(t *testing.T) {
+	l, _ := newTestLogger()
+	if err := l.RegisterLevel("audit", "error"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		level   string
+		enabled map[string]bool
+	}{
+		{"info", map[string]bool{"notice": true, "NOTICE": true, "audit": true, "debug": false, "bogus": false}},
+		{"warn", map[string]bool{"notice": false, "audit": true, "warn": true}},
+		{"error", map[string]bool{"notice": false, "audit": false, "error": true}},
+	}
+	for _, tt := range tests {
+		if err := l.SetLevel(tt.level); err != nil {
+			t.Fatal(err)
+		}
+		for name, want := range tt.enabled {
+			if got := l.IsLevelEnabled(name); got != want {
+				t.Errorf("at level %s, IsLevelEnabled(%q) = %v, want %v", tt.level, name, got, want)
+			}
+		}
+	}
+}
+
+func TestNoticeRendersItsLabel(t *testing.T) {
+	l, buf := newTestLogger()
+
+	l.Notice("maintenance at %d", 5)
+
+	if !strings.Contains(buf.String(), "NOTICE maintenance at 5") {
+		t.Errorf("output %q lacks the NOTICE label", buf.String())
+	}
+}
@@ -0,0 +1,132 @@
+package flogger
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// logfmtFormatter renders entries as logfmt, a single line of key=value pairs understood by tools such as Loki and Vector:
+// time, level and msg first, then the fields sorted by key, then the caller fields.
+type logfmtFormatter struct {
+	// timestampFormat is the layout of the time key, RFC3339 if empty.
+	timestampFormat string
+	// disableTimestamp omits the time key.
+	disableTimestamp bool
+	// trailingKeys lists the keys rendered last, in this order.
+	trailingKeys []string
+}
+
+// Format renders the entry as one logfmt line.
+func (f *logfmtFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	b := &bytes.Buffer{}
+
+	if !f.disableTimestamp {
+		timestampFormat := f.timestampFormat
+		if timestampFormat == "" {
+			timestampFormat = time.RFC3339
+		}
+		writeLogfmtPair(b, logrus.FieldKeyTime, entry.Time.Format(timestampFormat))
+	}
+	level := entry.Level.String()
+	if name, ok := levelName(entry); ok {
+		level = name
+	}
+	writeLogfmtPair(b, logrus.FieldKeyLevel, level)
+	writeLogfmtPair(b, logrus.FieldKeyMsg, entry.Message)
+
+	data := withoutLevelName(entry.Data)
+	for _, key := range f.orderedKeys(data) {
+		// Like the JSON formatter, keep fields clashing with the keys above under a "fields." prefix.
+		name := key
+		switch key {
+		case logrus.FieldKeyTime, logrus.FieldKeyLevel, logrus.FieldKeyMsg:
+			name = "fields." + key
+		}
+		writeLogfmtPair(b, name, logfmtValue(data[key]))
+	}
+
+	b.WriteByte('\n')
+	return b.Bytes(), nil
+}
+
+// orderedKeys returns the keys of data sorted, with the trailing keys moved to the end.
+func (f *logfmtFormatter) orderedKeys(data logrus.Fields) []string {
+	trailing := make(map[string]bool, len(f.trailingKeys))
+	for _, key := range f.trailingKeys {
+		trailing[key] = true
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		if !trailing[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range f.trailingKeys {
+		if _, ok := data[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// writeLogfmtPair writes key=value separated from the previous pair by a space, quoting value if needed.
+func writeLogfmtPair(b *bytes.Buffer, key, value string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(logfmtKey(key))
+	b.WriteByte('=')
+	if logfmtNeedsQuoting(value) {
+		b.WriteString(strconv.Quote(value))
+	} else {
+		b.WriteString(value)
+	}
+}
+
+// logfmtValue returns the string form of a field value, the message for errors.
+func logfmtValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case error:
+		return v.Error()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// logfmtKey replaces the characters a logfmt key cannot contain (spaces, '=', '"' and control characters) with '_'.
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '=' || r == '"' || unicode.IsSpace(r) || unicode.IsControl(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// logfmtNeedsQuoting reports whether value must be quoted: if it is empty or contains spaces, '=', '"',
+// control or non-printable characters.
+func logfmtNeedsQuoting(value string) bool {
+	if value == "" {
+		return true
+	}
+	for _, r := range value {
+		if r == '=' || r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package flogger
+
+import (
+	"bytes"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"testing"
+	"time"
+)
+
+func TestWriteLogfmtPairQuoting(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"plain", "bob", `k=bob`},
+		{"empty", "", `k=""`},
+		{"space", "hello world", `k="hello world"`},
+		{"quote", `say "hi"`, `k="say \"hi\""`},
+		{"lone quote", `"`, `k="\""`},
+		{"equals", "a=b", `k="a=b"`},
+		{"newline", "two\nlines", `k="two\nlines"`},
+		{"tab", "a\tb", `k="a\tb"`},
+		{"backslash", `C:\path`, `k=C:\path`},
+		{"unicode", "héllo", `k=héllo`},
+	}
+	for _, tt := range tests {
+		b := &bytes.Buffer{}
+		writeLogfmtPair(b, "k", tt.value)
+		if got := b.String(); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLogfmtKey(t *testing.T) {
+	tests := map[string]string{
+		"user":     "user",
+		"":         "_",
+		"a b":      "a_b",
+		"a=b":      "a_b",
+		`a"b`:      "a_b",
+		"db.rows":  "db.rows",
+		"line\nbr": "line_br",
+	}
+	for key, want := range tests {
+		if got := logfmtKey(key); got != want {
+			t.Errorf("logfmtKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestLogfmtFormat(t *testing.T) {
+	f := &logfmtFormatter{trailingKeys: []string{fileField, funcField}}
+	entry := logrus.NewEntry(logrus.New())
+	entry.Time = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	entry.Level = logrus.InfoLevel
+	entry.Message = "request done"
+	entry.Data = logrus.Fields{
+		fileField: "handler.go:42",
+		funcField: "svc.Handle",
+		"err":     errors.New("not found"),
+		"msg":     "clash",
+		"status":  200,
+	}
+
+	out, err := f.Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `time=2024-01-01T12:00:00Z level=info msg="request done" err="not found" fields.msg=clash status=200 file=handler.go:42 func=svc.Handle` + "\n"
+	if string(out) != want {
+		t.Errorf("got  %s\nwant %s", out, want)
+	}
+}
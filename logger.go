@@ -0,0 +1,1035 @@
+package flogger
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Logger is an independent logger instance with its own level, formatter and output.
+// Use New to create one when a component must not share the package-level logger's configuration.
+// A Logger is safe for concurrent use, including reconfiguring it while other goroutines are logging.
+type Logger struct {
+	// loggerCore is the configuration and state shared by the logger and its children, see Child.
+	*loggerCore
+	// fields are the fields carried by every entry of a child logger, nil for a logger created by New.
+	fields logrus.Fields
+}
+
+// loggerCore holds everything a Logger shares with the children created by Child.
+type loggerCore struct {
+	// log is the underlying logrus logger that performs the actual formatting and writing.
+	log *logrus.Logger
+
+	// formatter is the logrus logger's formatter; it is never replaced, only its current formatter is swapped.
+	formatter *formatterSwitch
+
+	// mu guards opts, outputs and the async settings, and serializes reconfiguration.
+	mu sync.Mutex
+	// opts holds the settings the current formatter was built from.
+	opts formatterOptions
+	// outputs holds the writers that entries are fanned out to.
+	outputs []io.Writer
+	// fallback receives the entries the outputs failed to write, nil if there is none.
+	fallback io.Writer
+	// asyncSize is the buffer size of asynchronous writing, zero when it is disabled.
+	asyncSize int
+	// asyncPolicy decides what happens when the asynchronous buffer is full.
+	asyncPolicy AsyncPolicy
+	// async is the writer moving writes to a background goroutine, nil when asynchronous writing is disabled.
+	async *asyncWriter
+	// syslog is the connection opened by SetSyslogOutput, nil if there is none.
+	syslog io.Closer
+	// files are the writers created by SetFileOutput, SetBatchOutput and SetTCPOutput, closed by Close.
+	files []io.Closer
+	// fileColors keeps the ANSI colors in the files opened by SetFileOutput.
+	fileColors atomic.Bool
+	// ring keeps the most recent entries once EnableRingBuffer is called, nil otherwise.
+	ring *ringBuffer
+	// audit writes the audit stream of AuditLog, nil until SetAuditOutput is called.
+	audit atomic.Pointer[logrus.Logger]
+
+	// caller moves the caller of every entry to the user's call site.
+	caller *callerHook
+	// clock sets the time of every entry once SetClock is called.
+	clock *clockHook
+	// defaults adds the default fields to every entry.
+	defaults *defaultFieldsHook
+	// context adds the correlation data carried by the entries' context.
+	context *contextHook
+	// providers merges the fields of the AddFieldProvider providers into every entry.
+	providers *fieldProviderHook
+	// goroutine adds the goroutine ID to every entry when enabled.
+	goroutine *goroutineHook
+	// redact masks the values of sensitive fields.
+	redact *redactHook
+	// stackTrace attaches stack traces to severe entries.
+	stackTrace *stackTraceHook
+	// truncate caps the length of field values and messages.
+	truncate *truncateHook
+	// levelOutputs writes entries to per-level writers when SplitOutput is enabled.
+	levelOutputs *levelOutputHook
+
+	// levels holds the global and per-component levels.
+	levels levels
+	// customLevels holds the pseudo-levels registered with RegisterLevel.
+	customLevels customLevels
+	// elevation restores the previous global level after SetLevelFor.
+	elevation levelElevation
+	// sampler drops identical messages over the SetSampling limit, nil when sampling is disabled.
+	sampler atomic.Pointer[sampler]
+	// tickSampler lets through the first messages per key then one in every few, nil unless SetSamplerTick is called.
+	tickSampler atomic.Pointer[tickSampler]
+	// levelSampler drops a share of the entries of some levels, nil unless SetLevelSampling is called.
+	levelSampler atomic.Pointer[levelSampler]
+	// dedup collapses identical consecutive entries, nil when deduplication is disabled.
+	dedup atomic.Pointer[deduper]
+	// exitCode is the code the process exits with after a Fatal entry.
+	exitCode atomic.Int32
+	// exitFunc is the function terminating the process after a Fatal entry once the outputs are flushed,
+	// nil for os.Exit.
+	exitFunc atomic.Pointer[func(code int)]
+	// repanic makes RecoverAndLog continue panicking after logging.
+	repanic atomic.Bool
+	// disabled drops every entry before it is formatted, see Disable.
+	disabled atomic.Bool
+	// once holds the keys WarnOnce has logged a message for.
+	once sync.Map
+}
+
+// New creates a new Logger that uses the custom formatter and logs at the Info level.
+func New() *Logger {
+	return newLogger(logrus.New(), true)
+}
+
+// NewWithLogrus creates a Logger on top of an existing logrus logger, keeping its level, output, caller reporting
+// and hooks, so a project can adopt flogger incrementally. flogger's hooks are registered on l and run before
+// the existing ones, which therefore see the default fields and the corrected caller like hooks added with AddHook.
+// With useFormatter, the custom formatter replaces l's formatter and the formatting options (SetFormatter,
+// SetColors, ...) apply; otherwise l's formatter is kept and they have no effect. l must not be used to
+// configure the output or the level afterwards, use the Logger instead, nor be passed to NewWithLogrus again.
+func NewWithLogrus(l *logrus.Logger, useFormatter bool) *Logger {
+	return newLogger(l, useFormatter)
+}
+
+// newLogger creates a Logger on top of l, installing the custom formatter if useFormatter is set.
+func newLogger(l *logrus.Logger, useFormatter bool) *Logger {
+	// Set the custom formatter as the logger's formatter.
+	opts := defaultFormatterOptions()
+	formatter := newFormatterSwitch(opts)
+	var entryFormatter logrus.Formatter = formatter
+	if useFormatter {
+		l.SetFormatter(formatter)
+	} else {
+		entryFormatter = l.Formatter
+	}
+
+	// Take the existing hooks off, they are registered again after flogger's own.
+	existing := l.ReplaceHooks(make(logrus.LevelHooks))
+
+	// Register the caller hook first, so every later hook sees the user's call site instead of flogger's wrappers.
+	caller := &callerHook{}
+	l.AddHook(caller)
+
+	// Take the entries' time from the clock once SetClock is called.
+	clock := &clockHook{}
+	l.AddHook(clock)
+
+	// Merge the provided fields into every entry, before the default ones so that they take precedence.
+	providers := &fieldProviderHook{}
+	l.AddHook(providers)
+
+	// Merge the default fields into every entry.
+	defaults := &defaultFieldsHook{}
+	l.AddHook(defaults)
+
+	// Add correlation data carried by the entry's context.
+	correlation := &contextHook{}
+	l.AddHook(correlation)
+
+	// Add the goroutine ID once SetReportGoroutineID is enabled.
+	goroutine := &goroutineHook{}
+	l.AddHook(goroutine)
+
+	// Mask sensitive fields once every other field has been added.
+	redact := &redactHook{}
+	l.AddHook(redact)
+
+	// Attach stack traces once SetStackTraceLevel is called.
+	stackTrace := &stackTraceHook{}
+	l.AddHook(stackTrace)
+
+	// Cap oversized values once they are final.
+	truncate := &truncateHook{}
+	l.AddHook(truncate)
+
+	// Write entries to the writers added with WithOutput. This hook formats the entry too,
+	// so it must come after every hook that changes the entry's fields.
+	l.AddHook(&entryOutputHook{formatter: entryFormatter})
+
+	// Route entries by level once SplitOutput is enabled. This hook formats the entry,
+	// so it must come after every hook that changes the entry's fields.
+	levelOutputs := &levelOutputHook{formatter: entryFormatter}
+	l.AddHook(levelOutputs)
+
+	for level, hooks := range existing {
+		l.Hooks[level] = append(l.Hooks[level], hooks...)
+	}
+
+	logger := &Logger{loggerCore: &loggerCore{
+		log:          l,
+		formatter:    formatter,
+		opts:         opts,
+		outputs:      []io.Writer{l.Out},
+		caller:       caller,
+		clock:        clock,
+		context:      correlation,
+		providers:    providers,
+		defaults:     defaults,
+		goroutine:    goroutine,
+		redact:       redact,
+		stackTrace:   stackTrace,
+		truncate:     truncate,
+		levelOutputs: levelOutputs,
+	}}
+	logger.exitCode.Store(1)
+
+	// Flush the outputs once the exit handlers ran, right before the process exits, so buffered lines and the
+	// fatal one itself are not lost. A logrus logger's own exit function keeps terminating the process.
+	if l.ExitFunc != nil {
+		exit := (func(code int))(l.ExitFunc)
+		logger.exitFunc.Store(&exit)
+	}
+	l.ExitFunc = logger.exit
+
+	// Notice ranks between Info and Warn.
+	_ = logger.RegisterLevel(NoticeLevel, logrus.WarnLevel.String())
+
+	// Keep the logrus logger's level, Info for a new one. Adjust this as needed for your application.
+	logger.setLevel(l.GetLevel())
+
+	return logger
+}
+
+// WithFields returns an Entry carrying the given structured fields.
+// The fields are copied, so later changes to the map do not affect the returned Entry.
+func (l *Logger) WithFields(fields map[string]interface{}) *Entry {
+	return &Entry{logger: l, entry: l.newEntry().WithFields(fields)}
+}
+
+// WithField returns an Entry carrying a single structured field.
+func (l *Logger) WithField(key string, value interface{}) *Entry {
+	return &Entry{logger: l, entry: l.newEntry().WithField(key, value)}
+}
+
+// WithError returns an Entry carrying err under the error field and, if err wraps other errors,
+// the message of every error in the chain under the error_chain field.
+func (l *Logger) WithError(err error) *Entry {
+	return &Entry{logger: l, entry: l.newEntry().WithFields(errorFields(err))}
+}
+
+// WithContext returns an Entry bound to ctx, so correlation data such as the trace ID is logged as fields.
+func (l *Logger) WithContext(ctx context.Context) *Entry {
+	return &Entry{logger: l, entry: l.newEntry().WithContext(ctx)}
+}
+
+// WithPrefix returns an Entry whose messages are tagged with prefix, which the text formatter renders highlighted
+// before the message, e.g. "INFO db: connected". JSON output carries it as the prefix field.
+func (l *Logger) WithPrefix(prefix string) *Entry {
+	return &Entry{logger: l, entry: l.newEntry().WithField(prefixField, prefix)}
+}
+
+// WithOutput returns an Entry whose entries are written to w in addition to the logger's outputs, e.g. to echo
+// status lines of a CLI tool to a progress file. The logger's output configuration is not changed.
+func (l *Logger) WithOutput(w io.Writer) *Entry {
+	return &Entry{logger: l, entry: l.newEntry().WithContext(withEntryOutputs(nil, []io.Writer{w}))}
+}
+
+// WithGroup returns an Entry whose fields are namespaced under name, e.g. WithGroup("db").WithField("rows", 3)
+// adds the db.rows field. Nested groups are joined with dots.
+func (l *Logger) WithGroup(name string) *Entry {
+	return (&Entry{logger: l, entry: l.newEntry()}).WithGroup(name)
+}
+
+// Child returns a logger that adds fields to every entry, on top of the fields of this logger.
+// Unlike an Entry, it is a full Logger that can be passed down a call stack and used to create further children.
+// The child shares this logger's configuration, such as the level and the outputs: changing either affects both.
+func (l *Logger) Child(fields map[string]interface{}) *Logger {
+	merged := make(logrus.Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &Logger{loggerCore: l.loggerCore, fields: merged}
+}
+
+// newEntry returns an entry carrying the logger's own fields, the starting point of every entry it logs.
+func (l *Logger) newEntry() *logrus.Entry {
+	entry := logrus.NewEntry(l.log)
+	if l.fields != nil {
+		entry = entry.WithFields(l.fields)
+	}
+	return entry
+}
+
+// log level methods
+
+// Info logs a message at the Info level with formatting.
+// It accepts a format string and variadic arguments, similar to fmt.Printf.
+func (l *Logger) Info(format string, args ...interface{}) {
+	l.logf(nil, logrus.InfoLevel, format, args)
+}
+
+// Warn logs a message at the Warn level with formatting.
+// It accepts a format string and variadic arguments, similar to fmt.Printf.
+func (l *Logger) Warn(format string, args ...interface{}) {
+	l.logf(nil, logrus.WarnLevel, format, args)
+}
+
+// Log logs a message with formatting at the level named level, chosen at runtime, e.g. from an HTTP status.
+// Levels registered with RegisterLevel are accepted too. An unknown level name is logged at Info, and a warning
+// naming it is logged the first time it is seen.
+func (l *Logger) Log(level, format string, args ...interface{}) {
+	l.logNamedLevel(nil, level, format, args)
+}
+
+// LogAt logs a message with formatting at the given level, chosen at runtime.
+func (l *Logger) LogAt(level logrus.Level, format string, args ...interface{}) {
+	l.logf(nil, level, format, args)
+}
+
+// logNamedLevel logs a message with formatting at the logrus or registered level named level, falling back to Info.
+func (l *Logger) logNamedLevel(entry *logrus.Entry, level, format string, args []interface{}) {
+	if _, ok := l.customLevels.get(level); ok {
+		l.logNamed(entry, level, format, args)
+		return
+	}
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		l.WarnOnce("flogger.unknown-level."+level, "unknown level %q, logging at info instead", level)
+		lvl = logrus.InfoLevel
+	}
+	l.logf(entry, lvl, format, args)
+}
+
+// WarnOnce logs a message at the Warn level with formatting the first time it is called with key, and drops it
+// on every later call with the same key, e.g. for deprecation notices. A call while the Warn level is disabled
+// does not count, so the message is still logged once the level is enabled.
+func (l *Logger) WarnOnce(key, format string, args ...interface{}) {
+	if !l.enabled(logrus.WarnLevel) {
+		return
+	}
+	if _, seen := l.once.LoadOrStore(key, struct{}{}); seen {
+		return
+	}
+	l.logf(nil, logrus.WarnLevel, format, args)
+}
+
+// Error logs a message at the Error level with formatting.
+// It accepts a format string and variadic arguments, similar to fmt.Printf.
+func (l *Logger) Error(format string, args ...interface{}) {
+	l.logf(nil, logrus.ErrorLevel, format, args)
+}
+
+// Debug logs a message at the Debug level with formatting.
+// It is a no-op unless the logger level is set to Debug or lower.
+func (l *Logger) Debug(format string, args ...interface{}) {
+	l.logf(nil, logrus.DebugLevel, format, args)
+}
+
+// Trace logs a message at the Trace level with formatting.
+// It is a no-op unless the logger level is set to Trace.
+func (l *Logger) Trace(format string, args ...interface{}) {
+	l.logf(nil, logrus.TraceLevel, format, args)
+}
+
+// Fatal logs a message at the Fatal level with formatting and then terminates the process with os.Exit(1).
+// The entry passes through the custom formatter like any other level, so the crash site is preserved.
+func (l *Logger) Fatal(format string, args ...interface{}) {
+	l.logf(nil, logrus.FatalLevel, format, args)
+}
+
+// Panic logs a message at the Panic level with formatting and then panics with the logged entry.
+// It accepts a format string and variadic arguments, similar to fmt.Printf.
+func (l *Logger) Panic(format string, args ...interface{}) {
+	l.logf(nil, logrus.PanicLevel, format, args)
+}
+
+// InfoContext logs a message at the Info level with formatting, including correlation data carried by ctx.
+func (l *Logger) InfoContext(ctx context.Context, format string, args ...interface{}) {
+	l.WithContext(ctx).Info(format, args...)
+}
+
+// WarnContext logs a message at the Warn level with formatting, including correlation data carried by ctx.
+func (l *Logger) WarnContext(ctx context.Context, format string, args ...interface{}) {
+	l.WithContext(ctx).Warn(format, args...)
+}
+
+// ErrorContext logs a message at the Error level with formatting, including correlation data carried by ctx.
+func (l *Logger) ErrorContext(ctx context.Context, format string, args ...interface{}) {
+	l.WithContext(ctx).Error(format, args...)
+}
+
+// Infoln logs its operands at the Info level without formatting, separated by spaces like fmt.Sprintln.
+// Unlike Info with arguments, a literal "%" in the operands is logged as is.
+func (l *Logger) Infoln(args ...interface{}) {
+	l.logln(nil, logrus.InfoLevel, args)
+}
+
+// Warnln logs its operands at the Warn level without formatting, separated by spaces like fmt.Sprintln.
+func (l *Logger) Warnln(args ...interface{}) {
+	l.logln(nil, logrus.WarnLevel, args)
+}
+
+// Errorln logs its operands at the Error level without formatting, separated by spaces like fmt.Sprintln.
+func (l *Logger) Errorln(args ...interface{}) {
+	l.logln(nil, logrus.ErrorLevel, args)
+}
+
+// Infow logs a message at the Info level with the given alternating keys and values as fields, e.g.
+//
+//	logger.Infow("saved", "id", 42, "dur", d)
+//
+// The message is logged as is, without formatting.
+func (l *Logger) Infow(msg string, keysAndValues ...interface{}) {
+	l.logw(nil, logrus.InfoLevel, msg, keysAndValues)
+}
+
+// Warnw logs a message at the Warn level with the given alternating keys and values as fields.
+func (l *Logger) Warnw(msg string, keysAndValues ...interface{}) {
+	l.logw(nil, logrus.WarnLevel, msg, keysAndValues)
+}
+
+// Errorw logs a message at the Error level with the given alternating keys and values as fields.
+func (l *Logger) Errorw(msg string, keysAndValues ...interface{}) {
+	l.logw(nil, logrus.ErrorLevel, msg, keysAndValues)
+}
+
+// InfoFunc logs the message returned by fn at the Info level, calling fn only if the level is enabled.
+func (l *Logger) InfoFunc(fn func() string) {
+	l.logFunc(nil, logrus.InfoLevel, fn)
+}
+
+// WarnFunc logs the message returned by fn at the Warn level, calling fn only if the level is enabled.
+func (l *Logger) WarnFunc(fn func() string) {
+	l.logFunc(nil, logrus.WarnLevel, fn)
+}
+
+// ErrorFunc logs the message returned by fn at the Error level, calling fn only if the level is enabled.
+func (l *Logger) ErrorFunc(fn func() string) {
+	l.logFunc(nil, logrus.ErrorLevel, fn)
+}
+
+// DebugFunc logs the message returned by fn at the Debug level, calling fn only if the level is enabled.
+func (l *Logger) DebugFunc(fn func() string) {
+	l.logFunc(nil, logrus.DebugLevel, fn)
+}
+
+// configuration methods
+
+// SetLevel sets the minimum level that will be logged by this logger.
+// It accepts level names such as "trace", "debug", "info", "warn", "error", "fatal" and "panic" (case-insensitive)
+// and returns an error if the name is not recognized, leaving the current level untouched.
+func (l *Logger) SetLevel(level string) error {
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	l.setLevel(lvl)
+	return nil
+}
+
+// setLevel sets the global level, keeping the logrus logger verbose enough for the component levels.
+// It replaces a temporary level set by SetLevelFor for good.
+func (l *Logger) setLevel(level logrus.Level) {
+	l.elevation.mu.Lock()
+	defer l.elevation.mu.Unlock()
+
+	l.elevation.stop()
+	l.levels.setGlobal(l.log, level)
+}
+
+// GetLevel returns the name of the current minimum log level of this logger (e.g. "info").
+// Component levels set with SetComponentLevel are not reflected.
+func (l *Logger) GetLevel() string {
+	return l.levels.getGlobal().String()
+}
+
+// IsLevelEnabled reports whether entries at the given level would be emitted, e.g. to skip expensive work
+// that only produces a log line. Component levels apply to children created with a component field.
+// Levels registered with RegisterLevel are accepted too. It returns false for unknown levels and while logging
+// is disabled.
+func (l *Logger) IsLevelEnabled(level string) bool {
+	// A registered level is filtered like the logrus level it maps to.
+	lvl, ok := l.customLevels.get(level)
+	if !ok {
+		var err error
+		if lvl, err = logrus.ParseLevel(level); err != nil {
+			return false
+		}
+	}
+	return l.enabled(lvl) && l.levels.allow(l.fields, lvl)
+}
+
+// SetOutput sets the writer that this logger's entries are written to (stderr by default).
+// It replaces every previously configured writer.
+func (l *Logger) SetOutput(w io.Writer) {
+	l.SetOutputs(w)
+}
+
+// SetOutputs replaces the configured writers with the given ones; every entry is written to all of them.
+// It also turns off SplitOutput.
+func (l *Logger) SetOutputs(writers ...io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.levelOutputs.set(nil)
+	l.outputs = append([]io.Writer(nil), writers...)
+	l.applyOutputs()
+}
+
+// SplitOutput writes Trace, Debug and Info entries to stdout and Warn, Error, Fatal and Panic entries to stderr,
+// following twelve-factor conventions. It replaces the configured writers until SetOutput or SetOutputs is called;
+// writers added afterwards with AddOutput still receive every entry.
+func (l *Logger) SplitOutput() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.outputs = nil
+	l.applyOutputs()
+	l.levelOutputs.set(map[logrus.Level]io.Writer{
+		logrus.TraceLevel: os.Stdout,
+		logrus.DebugLevel: os.Stdout,
+		logrus.InfoLevel:  os.Stdout,
+		logrus.WarnLevel:  os.Stderr,
+		logrus.ErrorLevel: os.Stderr,
+		logrus.FatalLevel: os.Stderr,
+		logrus.PanicLevel: os.Stderr,
+	})
+}
+
+// AddOutput adds a writer to the configured ones, so entries are written to it as well.
+func (l *Logger) AddOutput(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.outputs = append(l.outputs, w)
+	l.applyOutputs()
+}
+
+// applyOutputs installs the configured writers on the underlying logger. The caller must hold l.mu.
+func (l *Logger) applyOutputs() {
+	// The ring buffer receives every entry like another output.
+	writers := l.outputs
+	if l.ring != nil {
+		writers = append(writers[:len(writers):len(writers)], l.ring)
+	}
+
+	var out io.Writer
+	switch {
+	case l.disabled.Load(), len(writers) == 0:
+		out = io.Discard
+	case len(writers) == 1:
+		out = writers[0]
+	default:
+		out = io.MultiWriter(writers...)
+	}
+
+	// Retry failed writes on the fallback. It wraps the outputs below the asynchronous writer, so
+	// asynchronous writes fall back too.
+	if l.fallback != nil && out != io.Discard {
+		out = &fallbackWriter{primary: out, fallback: l.fallback}
+	}
+
+	// Queue writes for a background goroutine if asynchronous writing is enabled.
+	previous := l.async
+	l.async = nil
+	if l.asyncSize > 0 {
+		l.async = newAsyncWriter(out, l.asyncSize, l.asyncPolicy)
+		out = l.async
+	}
+	l.log.SetOutput(out)
+
+	// Write what the previous asynchronous writer still holds, now that no new entries reach it.
+	if previous != nil {
+		_ = previous.Close()
+	}
+
+	// The text formatter detects a terminal only once, so rebuild it for the new output.
+	l.formatter.current.Store(newFormatter(l.opts))
+}
+
+// Disable turns logging off entirely until Enable is called: entries are dropped before their arguments are formatted,
+// and the output becomes io.Discard for anything written directly through the logrus logger.
+// Fatal entries still exit the process and Panic entries still panic. It is meant for tests and benchmarks where even level filtering costs too much.
+func (l *Logger) Disable() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.disabled.Store(true)
+	l.applyOutputs()
+}
+
+// Enable turns logging back on after Disable, restoring the configured outputs.
+func (l *Logger) Enable() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.disabled.Store(false)
+	l.applyOutputs()
+}
+
+// Logrus returns the underlying logrus logger, an escape hatch for logrus features flogger does not wrap.
+// Changes made directly on it (e.g. replacing its formatter or output) bypass flogger's configuration,
+// and a later flogger setter may override them.
+func (l *Logger) Logrus() *logrus.Logger {
+	return l.log
+}
+
+// SetExitCode sets the code the process exits with after a Fatal entry (1 by default).
+func (l *Logger) SetExitCode(code int) {
+	l.exitCode.Store(int32(code))
+}
+
+// SetExitFunc replaces the function called with the exit code after a Fatal entry (os.Exit by default).
+// Tests can substitute a function that records the code instead of terminating the process; Fatal then returns.
+// The outputs are flushed before fn is called either way. A nil fn restores os.Exit.
+func (l *Logger) SetExitFunc(fn func(code int)) {
+	if fn == nil {
+		l.exitFunc.Store(nil)
+		return
+	}
+	l.exitFunc.Store(&fn)
+}
+
+// exit is the logrus logger's exit function: it flushes the buffered and asynchronous outputs, so the entries
+// explaining a crash reach them, and then calls the exit function. logrus calls it after the exit handlers.
+func (l *Logger) exit(code int) {
+	// Nothing sensible can be done about a failed flush on the way out.
+	_ = l.Flush()
+
+	exit := os.Exit
+	if fn := l.exitFunc.Load(); fn != nil {
+		exit = *fn
+	}
+	exit(code)
+}
+
+// AddFieldProvider registers fn to compute fields added to every entry, e.g. a mem_alloc field read on demand,
+// as a dynamic alternative to SetDefaultFields. Providers run on every entry in registration order, later ones
+// overriding the keys of earlier ones, and their fields take precedence over default fields but not over the
+// entry's own. A provider that panics contributes a field_provider_panic field with the panic value instead.
+func (l *Logger) AddFieldProvider(fn func() map[string]interface{}) {
+	l.providers.add(fn)
+}
+
+// SetDefaultFields replaces the fields attached to every entry of this logger.
+// Fields passed to WithFields or WithField override a default field with the same key, unless
+// SetDuplicateFieldPolicy says otherwise; an empty map clears them.
+func (l *Logger) SetDefaultFields(fields map[string]interface{}) {
+	l.defaults.set(fields)
+}
+
+// SetDuplicateFieldPolicy decides what happens when a default field and a field set on the entry, e.g. with
+// WithField, share a key: DuplicateFieldOverride keeps the entry's own (the default), DuplicateFieldKeep the
+// default, and DuplicateFieldRename both, logging the default as e.g. "user#2". It returns an error for an unknown
+// policy.
+func (l *Logger) SetDuplicateFieldPolicy(policy string) error {
+	if err := validateDuplicateFieldPolicy(policy); err != nil {
+		return err
+	}
+	l.defaults.setPolicy(policy)
+	return nil
+}
+
+// AddDefaultField attaches a single field to every entry of this logger, keeping the other default fields.
+func (l *Logger) AddDefaultField(key string, value interface{}) {
+	l.defaults.add(key, value)
+}
+
+// RedactKeys registers field keys whose values are replaced with "***" before formatting, e.g. "password" or "token".
+// Keys are matched case-insensitively and apply to per-call and default fields alike; calls accumulate.
+func (l *Logger) RedactKeys(keys ...string) {
+	l.redact.add(keys...)
+}
+
+// SetMaxFieldLength truncates every field value whose rendering is longer than n characters to its first n characters
+// followed by "...(truncated)", protecting downstream systems from pathological entries. Zero or less means no limit.
+// Truncated values are logged as strings.
+func (l *Logger) SetMaxFieldLength(n int) {
+	l.truncate.maxField.Store(int64(max(n, 0)))
+}
+
+// SetMaxMessageLength truncates messages longer than n characters like SetMaxFieldLength does field values.
+// Zero or less means no limit.
+func (l *Logger) SetMaxMessageLength(n int) {
+	l.truncate.maxMessage.Store(int64(max(n, 0)))
+}
+
+// AddHook registers a logrus hook on this logger, e.g. to ship errors to Sentry or count entries for metrics.
+// Hooks only fire for entries that pass the configured level (and are listed in the hook's Levels),
+// run in registration order after flogger's own hooks, and see the default fields and the corrected caller.
+func (l *Logger) AddHook(hook logrus.Hook) {
+	// Serialize with removeHook, which reads the registered hooks.
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.log.AddHook(hook)
+}
+
+// removeHook unregisters hook from every level. The hooks are replaced in one step, so entries logged concurrently
+// see either the previous or the new hooks, never a partial set.
+func (l *Logger) removeHook(hook logrus.Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	hooks := make(logrus.LevelHooks, len(l.log.Hooks))
+	for level, levelHooks := range l.log.Hooks {
+		for _, h := range levelHooks {
+			if h != hook {
+				hooks[level] = append(hooks[level], h)
+			}
+		}
+	}
+	l.log.ReplaceHooks(hooks)
+}
+
+// SetReportCaller enables or disables the func and file fields describing where each entry was logged from.
+// It is disabled by default because resolving the caller has a small cost on every log call.
+func (l *Logger) SetReportCaller(enabled bool) {
+	l.log.SetReportCaller(enabled)
+}
+
+// SetColors forces colored text output on or off.
+// Until it is called, colors are used only when the output is a terminal.
+func (l *Logger) SetColors(enabled bool) {
+	l.updateFormatter(func(opts *formatterOptions) {
+		if enabled {
+			opts.colors = colorsOn
+		} else {
+			opts.colors = colorsOff
+		}
+	})
+}
+
+// SetLevelColor sets the color of the level's label and field keys in colored text output, as an ansi style
+// such as "yellow", "red+b" (bold), "196" (256-color code) or "white+b:red" (with background).
+// Levels registered with RegisterLevel are accepted too. It returns an error for unknown levels or colors,
+// and has no effect while colors are disabled.
+func (l *Logger) SetLevelColor(level, color string) error {
+	if err := validateColor(color); err != nil {
+		return err
+	}
+
+	// A level registered with RegisterLevel has its own color.
+	if _, ok := l.customLevels.get(level); ok {
+		name := strings.ToLower(level)
+		l.updateFormatter(func(opts *formatterOptions) {
+			// Copy the map, formatters built from the previous options may still be reading it.
+			colors := make(map[string]string, len(opts.levelNameColors)+1)
+			for k, v := range opts.levelNameColors {
+				colors[k] = v
+			}
+			colors[name] = color
+			opts.levelNameColors = colors
+		})
+		return nil
+	}
+
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	l.updateFormatter(func(opts *formatterOptions) {
+		// Copy the map, formatters built from the previous options may still be reading it.
+		colors := make(map[logrus.Level]string, len(opts.levelColors)+1)
+		for k, v := range opts.levelColors {
+			colors[k] = v
+		}
+		colors[lvl] = color
+		opts.levelColors = colors
+	})
+	return nil
+}
+
+// SetFullFunctionName keeps the fully qualified caller function in the func field when enabled,
+// instead of the default short form such as "svc.(*Server).Handle".
+func (l *Logger) SetFullFunctionName(enabled bool) {
+	l.updateFormatter(func(opts *formatterOptions) {
+		opts.fullFunctionName = enabled
+	})
+}
+
+// SetCallerPathMode selects how the file field renders the caller's file: CallerPathBasename ("handler.go:42",
+// the default), CallerPathPackage ("svc/handler.go:42") to tell apart same-named files of different packages,
+// or CallerPathFull for the full path. It returns an error for an unknown mode.
+func (l *Logger) SetCallerPathMode(mode string) error {
+	if err := validateCallerPathMode(mode); err != nil {
+		return err
+	}
+	l.updateFormatter(func(opts *formatterOptions) {
+		opts.callerPath = mode
+	})
+	return nil
+}
+
+// SetExtractKVFromMessage promotes the key=value tokens at the end of messages to fields in JSON output, e.g.
+// "request done status=200" is logged as the message "request done" with a "status":"200" field, to help move
+// legacy messages to structured logging. Only trailing tokens that clearly are pairs qualify, values stay strings,
+// and fields set on the entry win over extracted ones. It is off by default; other formats are not affected.
+func (l *Logger) SetExtractKVFromMessage(enabled bool) {
+	l.updateFormatter(func(opts *formatterOptions) {
+		opts.extractKV = enabled
+	})
+}
+
+// SetFieldValueFormat selects how text output renders maps, structs, slices and arrays: FieldValueDefault
+// ("map[a:1 b:2]" with %+v, the default) or FieldValueJSON (`{"a":1,"b":2}`), which keeps nested data legible.
+// Errors and fmt.Stringers keep rendering themselves. It returns an error for an unknown format.
+func (l *Logger) SetFieldValueFormat(format string) error {
+	if err := validateFieldValueFormat(format); err != nil {
+		return err
+	}
+	l.updateFormatter(func(opts *formatterOptions) {
+		opts.valueFormat = format
+	})
+	return nil
+}
+
+// SetCallerFields selects which caller fields are added to entries while SetReportCaller is enabled:
+// func holds the calling function and file the file and line. Both are included by default.
+func (l *Logger) SetCallerFields(includeFunc, includeFile bool) {
+	l.updateFormatter(func(opts *formatterOptions) {
+		opts.includeFunc = includeFunc
+		opts.includeFile = includeFile
+	})
+}
+
+// SetCallerFieldKeys sets the keys of the caller fields, "func" and "file" by default, e.g. to "caller.function"
+// and "caller.file" to match the schema expected by a log aggregator. An empty key keeps the default.
+func (l *Logger) SetCallerFieldKeys(funcKey, fileKey string) {
+	if funcKey == "" {
+		funcKey = funcField
+	}
+	if fileKey == "" {
+		fileKey = fileField
+	}
+	l.updateFormatter(func(opts *formatterOptions) {
+		opts.funcKey = funcKey
+		opts.fileKey = fileKey
+	})
+}
+
+// SetCallerInPrefix renders the func and file fields in the text output's header, right after the timestamp,
+// e.g. "[2024-01-01 12:00:00] [svc.Handle handler.go:42]  INFO db: message", instead of as trailing fields.
+// It requires SetReportCaller; JSON and logfmt output keep the caller as fields.
+func (l *Logger) SetCallerInPrefix(enabled bool) {
+	l.updateFormatter(func(opts *formatterOptions) {
+		opts.callerInPrefix = enabled
+	})
+}
+
+// SetAlignMessages left-justifies the level label of text output to a fixed width, e.g. "WARN   " and "NOTICE ",
+// so messages line up in one column when scanning logs by eye. JSON and logfmt output are not affected.
+func (l *Logger) SetAlignMessages(enabled bool) {
+	l.updateFormatter(func(opts *formatterOptions) {
+		opts.alignMessages = enabled
+	})
+}
+
+// SetFieldSeparator sets what text output writes before every field, a single space by default, e.g. " | "
+// for "message | user=42 | status=200". An empty separator restores the default.
+func (l *Logger) SetFieldSeparator(sep string) {
+	l.updateFormatter(func(opts *formatterOptions) {
+		opts.fieldSeparator = sep
+	})
+}
+
+// SetFieldColors sets the ansi styles of field keys and values in colored text output, e.g. "cyan" and "white+h",
+// so keys stand out from values in busy lines. An empty keyColor keeps the level's color for keys and an empty
+// valueColor leaves values uncolored, the defaults. It returns an error for unknown colors, and has no effect while
+// colors are disabled.
+func (l *Logger) SetFieldColors(keyColor, valueColor string) error {
+	for _, color := range []string{keyColor, valueColor} {
+		if color == "" {
+			continue
+		}
+		if err := validateColor(color); err != nil {
+			return err
+		}
+	}
+
+	l.updateFormatter(func(opts *formatterOptions) {
+		opts.fieldKeyColor = keyColor
+		opts.fieldValueColor = valueColor
+	})
+	return nil
+}
+
+// SetFieldOrder renders the given field keys first, in this order, in text output.
+// The remaining fields follow sorted by key, and the caller fields (file and func) come last unless listed.
+func (l *Logger) SetFieldOrder(keys ...string) {
+	order := append([]string(nil), keys...)
+	l.updateFormatter(func(opts *formatterOptions) {
+		opts.fieldOrder = order
+	})
+}
+
+// SetTimestampFormat sets the layout used to render timestamps, e.g. time.RFC3339Nano.
+// An empty layout disables timestamps entirely.
+func (l *Logger) SetTimestampFormat(layout string) {
+	l.updateFormatter(func(opts *formatterOptions) {
+		opts.timestampFormat = layout
+		opts.disableTimestamp = layout == ""
+	})
+}
+
+// SetClock sets the function returning the time of every entry, time.Now by default, e.g. to freeze time in
+// golden-file tests. A nil fn restores time.Now.
+func (l *Logger) SetClock(fn func() time.Time) {
+	if fn == nil {
+		l.clock.now.Store(nil)
+		return
+	}
+	l.clock.now.Store(&fn)
+}
+
+// SetLineTransform sets a function applied to every formatted line, newline included, right before it is written,
+// e.g. to prepend a fixed header or escape lines for a particular transport, without writing a whole formatter.
+// It runs once per entry, possibly from several goroutines at once, and may modify and return its argument.
+// Lines formatted by a logrus formatter kept with NewWithLogrus are not transformed. A nil fn removes the transform.
+func (l *Logger) SetLineTransform(fn func([]byte) []byte) {
+	if fn == nil {
+		l.formatter.transform.Store(nil)
+		return
+	}
+	l.formatter.transform.Store(&fn)
+}
+
+// SetUTC renders timestamps in UTC when enabled, and in local time (the default) otherwise.
+func (l *Logger) SetUTC(enabled bool) {
+	l.updateFormatter(func(opts *formatterOptions) {
+		opts.utc = enabled
+	})
+}
+
+// SetOmitEmptyFields drops the fields whose value is nil, an empty string or an empty slice or map from the output
+// of every format, which keeps lines clean when fields are populated conditionally. It is disabled by default.
+func (l *Logger) SetOmitEmptyFields(enabled bool) {
+	l.updateFormatter(func(opts *formatterOptions) {
+		opts.omitEmpty = enabled
+	})
+}
+
+// PrettyJSONFields makes the text formatter indent the values of the given fields that hold a JSON object or array,
+// as a string, []byte or json.RawMessage, which keeps logged payloads readable during development.
+// Each call replaces the previous keys; calling it without keys turns the option off. JSON output is not affected.
+func (l *Logger) PrettyJSONFields(keys ...string) {
+	l.updateFormatter(func(opts *formatterOptions) {
+		opts.prettyJSONKeys = append([]string(nil), keys...)
+	})
+}
+
+// SetRelativeTimestamps makes the text formatter render the time elapsed since the epoch, e.g. "[+1.234567ms]",
+// instead of the wall-clock time, which makes latencies between consecutive lines easy to read during development.
+// The epoch is the time of the first entry unless set with SetTimestampEpoch. JSON output is not affected.
+func (l *Logger) SetRelativeTimestamps(enabled bool) {
+	l.updateFormatter(func(opts *formatterOptions) {
+		opts.relativeTimestamps = enabled
+	})
+}
+
+// SetTimestampEpoch sets the reference time of relative timestamps, see SetRelativeTimestamps.
+func (l *Logger) SetTimestampEpoch(epoch time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.opts.epoch.start.Store(&epoch)
+}
+
+// SetReportGoroutineID adds the ID of the logging goroutine as the goroutine field to every entry when enabled.
+// It is off by default because obtaining the ID requires parsing the goroutine's stack on every call.
+func (l *Logger) SetReportGoroutineID(enabled bool) {
+	l.goroutine.enabled.Store(enabled)
+}
+
+// SetFormatter selects the output format: FormatText ("text", the default), FormatJSON ("json"), FormatLogfmt ("logfmt")
+// or FormatGCP ("gcp", JSON for Google Cloud Logging).
+// It returns an error for unknown formats, leaving the current format untouched.
+func (l *Logger) SetFormatter(format string) error {
+	if err := validateFormat(format); err != nil {
+		return err
+	}
+	l.updateFormatter(func(opts *formatterOptions) {
+		opts.format = format
+	})
+	return nil
+}
+
+// SetJSONMessageKey renames the key of the message in JSON output, "msg" by default, e.g. to "message" to match
+// an ingestion schema. An empty key restores the default.
+func (l *Logger) SetJSONMessageKey(key string) {
+	l.updateFormatter(func(opts *formatterOptions) {
+		opts.jsonMessageKey = key
+	})
+}
+
+// SetJSONLevelKey renames the key of the level in JSON output, "level" by default. An empty key restores the default.
+func (l *Logger) SetJSONLevelKey(key string) {
+	l.updateFormatter(func(opts *formatterOptions) {
+		opts.jsonLevelKey = key
+	})
+}
+
+// SetJSONTimeKey renames the key of the timestamp in JSON output, "time" by default. An empty key restores the default.
+func (l *Logger) SetJSONTimeKey(key string) {
+	l.updateFormatter(func(opts *formatterOptions) {
+		opts.jsonTimeKey = key
+	})
+}
+
+// UseJSONFormatter switches this logger's output to JSON, keeping the func and file fields.
+// It is a shorthand for SetFormatter(FormatJSON).
+func (l *Logger) UseJSONFormatter() {
+	_ = l.SetFormatter(FormatJSON)
+}
+
+// AutoFormat selects the colored text format if the output is an interactive terminal and JSON otherwise,
+// giving readable local output and machine-readable production logs without configuration.
+// The decision is made from the outputs configured at the time of the call, and SetFormatter or SetColors
+// still override it afterwards.
+func (l *Logger) AutoFormat() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Every output must be a terminal, a redirected one would otherwise receive escape codes.
+	terminal := len(l.outputs) > 0
+	for _, w := range l.outputs {
+		terminal = terminal && isTerminal(w)
+	}
+
+	if terminal {
+		l.opts.format = FormatText
+		l.opts.colors = colorsOn
+	} else {
+		l.opts.format = FormatJSON
+	}
+	l.formatter.current.Store(newFormatter(l.opts))
+}
+
+// updateFormatter applies fn to the formatter options and installs a formatter built from the result.
+func (l *Logger) updateFormatter(fn func(opts *formatterOptions)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	fn(&l.opts)
+	l.formatter.current.Store(newFormatter(l.opts))
+}
@@ -0,0 +1,127 @@
+package flogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"io"
+	"sync"
+	"testing"
+)
+
+// newJSONLogger returns a Logger writing JSON to the returned buffer.
+func newJSONLogger(t *testing.T) (*Logger, *bytes.Buffer) {
+	t.Helper()
+	l, buf := newTestLogger()
+	if err := l.SetFormatter(FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	return l, buf
+}
+
+// decodeJSON parses the single JSON entry in data.
+func decodeJSON(t *testing.T, data []byte) map[string]interface{} {
+	t.Helper()
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("invalid JSON entry %q: %v", data, err)
+	}
+	return fields
+}
+
+func TestConcurrentConfiguration(t *testing.T) {
+	l := New()
+	l.SetOutput(io.Discard)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			child := l.Child(map[string]interface{}{"worker": i})
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				l.Info("info %d", i)
+				l.Debug("debug %d", i)
+				child.WithField("k", i).Warn("warn")
+				_ = l.IsLevelEnabled("debug")
+			}
+		}(i)
+	}
+
+	levels := []string{"debug", "info", "warn", "trace"}
+	for i := 0; i < 200; i++ {
+		if err := l.SetLevel(levels[i%len(levels)]); err != nil {
+			t.Fatal(err)
+		}
+		l.SetOutput(io.Discard)
+		if err := l.SetFormatter([]string{FormatText, FormatJSON, FormatLogfmt}[i%3]); err != nil {
+			t.Fatal(err)
+		}
+		l.SetReportCaller(i%2 == 0)
+		l.AddDefaultField("iteration", i)
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func TestDisabledPanicStillPanics(t *testing.T) {
+	l, buf := newTestLogger()
+	l.Disable()
+
+	for name, panics := range map[string]func(){
+		"Panic": func() { l.Panic("boom %d", 1) },
+		"LogAt": func() { l.LogAt(logrus.PanicLevel, "boom") },
+		"Entry": func() { l.WithField("k", 1).Panic("boom %d", 1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s returned normally while disabled", name)
+				}
+			}()
+			panics()
+		}()
+	}
+	if buf.Len() != 0 {
+		t.Errorf("disabled logger wrote %q", buf.String())
+	}
+}
+
+func TestDisabledFatalStillExits(t *testing.T) {
+	l, _ := newTestLogger()
+	exits := 0
+	l.SetExitFunc(func(int) { exits++ })
+	l.Disable()
+
+	l.Fatal("fatal")
+	l.Log("fatal", "fatal")
+	if exits != 2 {
+		t.Errorf("exits = %d, want 2", exits)
+	}
+}
+
+// BenchmarkInfo measures an enabled Info call writing to io.Discard, the baseline of BenchmarkInfoDisabled.
+func BenchmarkInfo(b *testing.B) {
+	l := New()
+	l.SetOutput(io.Discard)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("request %d done in %s", i, "5ms")
+	}
+}
+
+// BenchmarkInfoDisabled measures an Info call after Disable, which returns before formatting the arguments.
+func BenchmarkInfoDisabled(b *testing.B) {
+	l := New()
+	l.Disable()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("request %d done in %s", i, "5ms")
+	}
+}
@@ -0,0 +1,63 @@
+// Package metrics counts flogger log entries per level with Prometheus.
+// It lives in its own package so that only programs importing it depend on the Prometheus client.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/seyedali-dev/flogger"
+	"github.com/sirupsen/logrus"
+)
+
+// linesTotalName is the name of the counter of emitted entries.
+const linesTotalName = "log_lines_total"
+
+// EnableMetrics registers a log_lines_total{level="..."} counter with registerer and increments it for every entry
+// emitted by the package-level flogger logger.
+// Registering the counter with the same registerer twice fails with a prometheus.AlreadyRegisteredError.
+func EnableMetrics(registerer prometheus.Registerer) error {
+	hook, err := newCounterHook(registerer)
+	if err != nil {
+		return err
+	}
+	flogger.AddHook(hook)
+	return nil
+}
+
+// EnableLoggerMetrics is like EnableMetrics for the given logger.
+func EnableLoggerMetrics(logger *flogger.Logger, registerer prometheus.Registerer) error {
+	hook, err := newCounterHook(registerer)
+	if err != nil {
+		return err
+	}
+	logger.AddHook(hook)
+	return nil
+}
+
+// newCounterHook creates the log_lines_total counter, registers it with registerer and returns a hook incrementing it.
+func newCounterHook(registerer prometheus.Registerer) (*counterHook, error) {
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: linesTotalName,
+		Help: "Number of log entries emitted, by level.",
+	}, []string{"level"})
+	if err := registerer.Register(counter); err != nil {
+		return nil, err
+	}
+	return &counterHook{counter: counter}, nil
+}
+
+// counterHook increments the counter of the entry's level for every entry.
+type counterHook struct {
+	// counter is the log_lines_total counter, labeled by level.
+	counter *prometheus.CounterVec
+}
+
+// Levels returns all levels, so every emitted entry is counted.
+func (h *counterHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire increments the counter of the entry's level.
+func (h *counterHook) Fire(entry *logrus.Entry) error {
+	h.counter.WithLabelValues(entry.Level.String()).Inc()
+	return nil
+}
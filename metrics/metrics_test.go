@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/seyedali-dev/flogger"
+	"io"
+	"testing"
+)
+
+// counts returns the log_lines_total counter values of registry by level.
+func counts(t *testing.T, registry *prometheus.Registry) map[string]float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != linesTotalName {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "level" {
+					values[label.GetValue()] = metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return values
+}
+
+func TestEnableLoggerMetricsCountsPerLevel(t *testing.T) {
+	logger := flogger.New()
+	logger.SetOutput(io.Discard)
+	registry := prometheus.NewRegistry()
+	if err := EnableLoggerMetrics(logger, registry); err != nil {
+		t.Fatal(err)
+	}
+
+	logger.Info("one")
+	logger.Info("two")
+	logger.Warn("three")
+	logger.Error("four")
+	// Entries below the logger's level are not emitted, so they are not counted.
+	logger.Debug("hidden")
+
+	got := counts(t, registry)
+	want := map[string]float64{"info": 2, "warning": 1, "error": 1}
+	for level, n := range want {
+		if got[level] != n {
+			t.Errorf("%s count = %v, want %v", level, got[level], n)
+		}
+	}
+	if _, ok := got["debug"]; ok {
+		t.Errorf("counts %v include the disabled Debug level", got)
+	}
+}
+
+func TestEnableLoggerMetricsTwice(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	if err := EnableLoggerMetrics(flogger.New(), registry); err != nil {
+		t.Fatal(err)
+	}
+
+	err := EnableLoggerMetrics(flogger.New(), registry)
+	var already prometheus.AlreadyRegisteredError
+	if !errors.As(err, &already) {
+		t.Errorf("second registration error = %v, want a prometheus.AlreadyRegisteredError", err)
+	}
+}
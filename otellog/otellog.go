@@ -0,0 +1,157 @@
+// Package otellog exports flogger log entries as OpenTelemetry log records through the OTel log SDK, so they
+// correlate with traces in an OTel backend. It lives in its own package so that only programs importing it
+// depend on OpenTelemetry.
+package otellog
+
+import (
+	"context"
+	"fmt"
+	"github.com/seyedali-dev/flogger"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"time"
+)
+
+// instrumentationName is the name of the OTel logger the records are emitted with.
+const instrumentationName = "github.com/seyedali-dev/flogger"
+
+// attribute keys of the caller, following the OTel semantic conventions.
+const (
+	// codeFunctionKey holds the function the entry was logged from.
+	codeFunctionKey = "code.function"
+	// codeFilepathKey holds the file the entry was logged from.
+	codeFilepathKey = "code.filepath"
+	// codeLinenoKey holds the line the entry was logged from.
+	codeLinenoKey = "code.lineno"
+)
+
+// Enable emits every entry of the package-level flogger logger as a log record through provider, e.g. the one
+// returned by NewOTLPProvider. Entries keep being written to the configured outputs as well.
+func Enable(provider log.LoggerProvider) {
+	flogger.AddHook(NewHook(provider))
+}
+
+// EnableLogger is like Enable for the given logger.
+func EnableLogger(logger *flogger.Logger, provider log.LoggerProvider) {
+	logger.AddHook(NewHook(provider))
+}
+
+// NewOTLPProvider creates a LoggerProvider batching records to the OTLP/HTTP endpoint, a host and port such as
+// "collector:4318", or to the one configured by the OTEL_EXPORTER_OTLP_* environment variables if endpoint is empty.
+// Further options are passed to the exporter, e.g. otlploghttp.WithInsecure() for a collector without TLS.
+// Shut the provider down before exiting to send the pending records.
+func NewOTLPProvider(ctx context.Context, endpoint string, opts ...otlploghttp.Option) (*sdklog.LoggerProvider, error) {
+	if endpoint != "" {
+		opts = append([]otlploghttp.Option{otlploghttp.WithEndpoint(endpoint)}, opts...)
+	}
+	exporter, err := otlploghttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("otellog: create OTLP exporter: %w", err)
+	}
+	return sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter))), nil
+}
+
+// Hook is a logrus hook converting every entry into an OTel log record: the message becomes the body,
+// the level the severity, and the fields and the caller attributes. The trace and span IDs are taken from the
+// span in the entry's context, see flogger.Logger.WithContext.
+type Hook struct {
+	// logger emits the records.
+	logger log.Logger
+}
+
+// NewHook creates a Hook emitting records through provider.
+func NewHook(provider log.LoggerProvider) *Hook {
+	return &Hook{logger: provider.Logger(instrumentationName)}
+}
+
+// Levels returns all levels, the flogger logger has already filtered the entries.
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire emits the entry as a log record.
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	// The SDK takes the trace and span IDs from the span in ctx.
+	ctx := entry.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var record log.Record
+	record.SetTimestamp(entry.Time)
+	record.SetObservedTimestamp(time.Now())
+	record.SetSeverity(severity(entry.Level))
+	record.SetSeverityText(flogger.LevelName(entry))
+	record.SetBody(log.StringValue(entry.Message))
+
+	attrs := make([]log.KeyValue, 0, len(entry.Data)+3)
+	for k, v := range entry.Data {
+		if k == flogger.LevelNameField {
+			continue
+		}
+		attrs = append(attrs, log.KeyValue{Key: k, Value: value(v)})
+	}
+	if entry.HasCaller() {
+		attrs = append(attrs,
+			log.String(codeFunctionKey, entry.Caller.Function),
+			log.String(codeFilepathKey, entry.Caller.File),
+			log.Int(codeLinenoKey, entry.Caller.Line),
+		)
+	}
+	record.AddAttributes(attrs...)
+
+	h.logger.Emit(ctx, record)
+	return nil
+}
+
+// severity maps a logrus level to its OTel severity number.
+func severity(level logrus.Level) log.Severity {
+	switch level {
+	case logrus.PanicLevel:
+		return log.SeverityFatal4
+	case logrus.FatalLevel:
+		return log.SeverityFatal
+	case logrus.ErrorLevel:
+		return log.SeverityError
+	case logrus.WarnLevel:
+		return log.SeverityWarn
+	case logrus.InfoLevel:
+		return log.SeverityInfo
+	case logrus.DebugLevel:
+		return log.SeverityDebug
+	default:
+		return log.SeverityTrace
+	}
+}
+
+// value converts a field value to an OTel attribute value, falling back to its fmt rendering for other types.
+func value(v interface{}) log.Value {
+	switch v := v.(type) {
+	case string:
+		return log.StringValue(v)
+	case bool:
+		return log.BoolValue(v)
+	case int:
+		return log.IntValue(v)
+	case int64:
+		return log.Int64Value(v)
+	case int32:
+		return log.Int64Value(int64(v))
+	case uint32:
+		return log.Int64Value(int64(v))
+	case float64:
+		return log.Float64Value(v)
+	case float32:
+		return log.Float64Value(float64(v))
+	case []byte:
+		return log.BytesValue(v)
+	case error:
+		return log.StringValue(v.Error())
+	case fmt.Stringer:
+		return log.StringValue(v.String())
+	default:
+		return log.StringValue(fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,99 @@
+package otellog
+
+import (
+	"github.com/seyedali-dev/flogger"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/logtest"
+	"io"
+	"testing"
+)
+
+// records returns the records emitted through recorder.
+func records(recorder *logtest.Recorder) []logtest.EmittedRecord {
+	var emitted []logtest.EmittedRecord
+	for _, scope := range recorder.Result() {
+		emitted = append(emitted, scope.Records...)
+	}
+	return emitted
+}
+
+// attributes returns the attributes of record by key.
+func attributes(record log.Record) map[string]log.Value {
+	attrs := make(map[string]log.Value, record.AttributesLen())
+	record.WalkAttributes(func(kv log.KeyValue) bool {
+		attrs[kv.Key] = kv.Value
+		return true
+	})
+	return attrs
+}
+
+func TestEnableLoggerEmitsRecords(t *testing.T) {
+	logger := flogger.New()
+	logger.SetOutput(io.Discard)
+	recorder := logtest.NewRecorder()
+	EnableLogger(logger, recorder)
+
+	logger.WithField("user", "bob").WithField("attempt", 2).Warn("login failed for %s", "bob")
+
+	emitted := records(recorder)
+	if len(emitted) != 1 {
+		t.Fatalf("emitted %d records, want 1", len(emitted))
+	}
+	record := emitted[0].Record
+	if got := record.Severity(); got != log.SeverityWarn {
+		t.Errorf("severity = %v, want %v", got, log.SeverityWarn)
+	}
+	if got := record.SeverityText(); got != "warning" {
+		t.Errorf("severity text = %q, want warning", got)
+	}
+	if got := record.Body().AsString(); got != "login failed for bob" {
+		t.Errorf("body = %q, want the formatted message", got)
+	}
+
+	attrs := attributes(record)
+	if got := attrs["user"].AsString(); got != "bob" {
+		t.Errorf("user attribute = %q, want bob", got)
+	}
+	if got := attrs["attempt"].AsInt64(); got != 2 {
+		t.Errorf("attempt attribute = %d, want 2", got)
+	}
+	if _, ok := attrs[flogger.LevelNameField]; ok {
+		t.Errorf("attributes %v hold the internal level name field", attrs)
+	}
+}
+
+func TestEnableLoggerSkipsDisabledLevels(t *testing.T) {
+	logger := flogger.New()
+	logger.SetOutput(io.Discard)
+	recorder := logtest.NewRecorder()
+	EnableLogger(logger, recorder)
+
+	logger.Debug("hidden")
+	logger.Error("shown")
+
+	emitted := records(recorder)
+	if len(emitted) != 1 || emitted[0].Severity() != log.SeverityError {
+		t.Errorf("emitted %d records, want only the Error one", len(emitted))
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	for level, want := range map[string]log.Severity{
+		"trace": log.SeverityTrace, "debug": log.SeverityDebug, "info": log.SeverityInfo,
+		"warn": log.SeverityWarn, "error": log.SeverityError,
+	} {
+		logger := flogger.New()
+		logger.SetOutput(io.Discard)
+		if err := logger.SetLevel("trace"); err != nil {
+			t.Fatal(err)
+		}
+		recorder := logtest.NewRecorder()
+		EnableLogger(logger, recorder)
+
+		logger.Log(level, "msg")
+
+		if emitted := records(recorder); len(emitted) != 1 || emitted[0].Severity() != want {
+			t.Errorf("%s: records %v, want one with severity %v", level, emitted, want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Package oteltrace adds the trace and span IDs of the active OpenTelemetry span to flogger entries logged with a
+// context, such as those of InfoContext or WithContext, to correlate logs with distributed traces. It lives in its
+// own package so that only programs importing it depend on OpenTelemetry.
+package oteltrace
+
+import (
+	"context"
+	"github.com/seyedali-dev/flogger"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanIDField is the field under which the span ID of the entry's context is logged.
+const SpanIDField = "span_id"
+
+// Enable adds the trace and span IDs of the span carried by an entry's context to every entry of the package-level
+// flogger logger, as the trace_id and span_id fields. They are added while the entry is enriched, so every output
+// sees them, including SplitOutput and WithOutput writers.
+func Enable() {
+	flogger.AddContextExtractor(Fields)
+}
+
+// EnableLogger is like Enable for the given logger.
+func EnableLogger(logger *flogger.Logger) {
+	logger.AddContextExtractor(Fields)
+}
+
+// Fields returns the trace_id and span_id fields of the span in ctx, nil if it carries no valid span.
+func Fields(ctx context.Context) map[string]interface{} {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return nil
+	}
+	return map[string]interface{}{
+		flogger.TraceIDField: spanContext.TraceID().String(),
+		SpanIDField:          spanContext.SpanID().String(),
+	}
+}
+
+// Hook is a logrus hook adding the trace and span IDs of the span in the entry's context as fields, for logrus
+// loggers not managed by flogger; flogger loggers use Enable or EnableLogger instead.
+// Entries logged without a context, or with one carrying no valid span, are left alone.
+type Hook struct{}
+
+// NewHook creates a Hook.
+func NewHook() *Hook {
+	return &Hook{}
+}
+
+// Levels returns all levels, since correlation data applies regardless of level.
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds the IDs of the entry's span, unless the entry already sets the respective field, e.g. from
+// flogger.ContextWithTraceID.
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	// The entry was not logged with a context.
+	if entry.Context == nil {
+		return nil
+	}
+
+	for k, v := range Fields(entry.Context) {
+		if _, exists := entry.Data[k]; !exists {
+			entry.Data[k] = v
+		}
+	}
+	return nil
+}
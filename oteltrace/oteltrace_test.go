@@ -0,0 +1,90 @@
+package oteltrace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/seyedali-dev/flogger"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+)
+
+// mockSpanContext returns a context carrying a span context with fixed IDs, as a tracer would.
+func mockSpanContext(t *testing.T) context.Context {
+	t.Helper()
+	traceID, err := trace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spanID, err := trace.SpanIDFromHex("0102030405060708")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled})
+	return trace.ContextWithSpanContext(context.Background(), spanContext)
+}
+
+// decode parses the single JSON line in buf.
+func decode(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+	var fields map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &fields); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	return fields
+}
+
+func TestEnableLoggerAddsSpanIDs(t *testing.T) {
+	logger := flogger.New()
+	var main, side bytes.Buffer
+	logger.SetOutput(&main)
+	if err := logger.SetFormatter(flogger.FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	EnableLogger(logger)
+
+	logger.WithContext(mockSpanContext(t)).WithOutput(&side).Info("traced")
+
+	for name, buf := range map[string]*bytes.Buffer{"main": &main, "side": &side} {
+		fields := decode(t, buf)
+		if got := fields[flogger.TraceIDField]; got != "0102030405060708090a0b0c0d0e0f10" {
+			t.Errorf("%s output trace_id = %v", name, got)
+		}
+		if got := fields[SpanIDField]; got != "0102030405060708" {
+			t.Errorf("%s output span_id = %v", name, got)
+		}
+	}
+}
+
+func TestEnableLoggerWithoutSpan(t *testing.T) {
+	logger := flogger.New()
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	if err := logger.SetFormatter(flogger.FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	EnableLogger(logger)
+
+	logger.InfoContext(context.Background(), "untraced")
+
+	fields := decode(t, &buf)
+	if _, ok := fields[SpanIDField]; ok {
+		t.Errorf("entry without a span has span_id %v", fields[SpanIDField])
+	}
+}
+
+func TestEnableLoggerKeepsExplicitTraceID(t *testing.T) {
+	logger := flogger.New()
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	if err := logger.SetFormatter(flogger.FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	EnableLogger(logger)
+
+	logger.InfoContext(flogger.ContextWithTraceID(mockSpanContext(t), "explicit"), "traced")
+
+	if got := decode(t, &buf)[flogger.TraceIDField]; got != "explicit" {
+		t.Errorf("trace_id = %v, want the explicit one", got)
+	}
+}
@@ -0,0 +1,232 @@
+package flogger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// NewFileWriter creates a rotating file writer for the given path.
+// The file is rotated once it reaches maxSizeMB megabytes; at most maxBackups rotated files are kept,
+// and rotated files older than maxAgeDays days are removed (zero disables the respective limit).
+// Missing parent directories are created, and an error is returned if the file cannot be opened for writing.
+// ANSI escape sequences are stripped from everything written, so a file never contains colors, even forced ones.
+//
+// The returned writer can be combined with other writers, e.g. io.MultiWriter(os.Stderr, w).
+func NewFileWriter(path string, maxSizeMB, maxBackups, maxAgeDays int) (io.WriteCloser, error) {
+	return newFileWriter(path, maxSizeMB, maxBackups, maxAgeDays, false)
+}
+
+// newFileWriter creates the rotating file writer of NewFileWriter, keeping ANSI escape sequences if keepColors is set.
+func newFileWriter(path string, maxSizeMB, maxBackups, maxAgeDays int, keepColors bool) (io.WriteCloser, error) {
+	// Create the parent directories if they are missing.
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("flogger: create log directory: %w", err)
+	}
+
+	// Open the file once up front so an unwritable path is reported now rather than on the first log call.
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("flogger: open log file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("flogger: open log file: %w", err)
+	}
+
+	w := &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    maxSizeMB,
+		MaxBackups: maxBackups,
+		MaxAge:     maxAgeDays,
+	}
+	if keepColors {
+		return w, nil
+	}
+	return &ansiStripWriter{WriteCloser: w}, nil
+}
+
+// ansiStripWriter removes ANSI escape sequences, such as the colors of the text formatter, from what it writes.
+type ansiStripWriter struct {
+	// WriteCloser receives the stripped writes and is closed by Close.
+	io.WriteCloser
+}
+
+// Write writes p without its ANSI escape sequences and reports the whole of p as written.
+func (w *ansiStripWriter) Write(p []byte) (int, error) {
+	if _, err := w.WriteCloser.Write(stripANSI(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// stripANSI returns p without its ANSI control sequences ("\x1b[" followed by parameters and a final byte
+// in the range '@' to '~'), copying it only if it holds any.
+func stripANSI(p []byte) []byte {
+	if bytes.IndexByte(p, 0x1b) < 0 {
+		return p
+	}
+
+	stripped := make([]byte, 0, len(p))
+	for i := 0; i < len(p); i++ {
+		if p[i] != 0x1b || i+1 >= len(p) || p[i+1] != '[' {
+			stripped = append(stripped, p[i])
+			continue
+		}
+		// Skip the parameters up to and including the final byte.
+		i += 2
+		for i < len(p) && (p[i] < '@' || p[i] > '~') {
+			i++
+		}
+	}
+	return stripped
+}
+
+// SetFileOutput redirects this logger's output to a rotating file, see NewFileWriter for the parameters.
+// Colors are stripped from the file unless SetFileColors(true) was called before.
+func (l *Logger) SetFileOutput(path string, maxSizeMB, maxBackups, maxAgeDays int) error {
+	w, err := newFileWriter(path, maxSizeMB, maxBackups, maxAgeDays, l.fileColors.Load())
+	if err != nil {
+		return err
+	}
+	l.SetOutput(w)
+
+	// The logger opened the file, so it is the one closing it, see Close.
+	l.mu.Lock()
+	l.files = append(l.files, w)
+	l.mu.Unlock()
+	return nil
+}
+
+// SetFileColors keeps the ANSI colors in the files opened by later SetFileOutput calls when enabled, for the rare
+// case of log files meant to be viewed with a pager such as "less -R"; they are stripped by default.
+// The text output is only colored if SetColors(true) forces it, since a file is never a terminal.
+func (l *Logger) SetFileColors(enabled bool) {
+	l.fileColors.Store(enabled)
+}
+
+// fallbackWriter writes to its primary writer and, if that fails, writes the same bytes to its fallback writer.
+type fallbackWriter struct {
+	// primary receives every write.
+	primary io.Writer
+	// fallback receives the writes the primary writer failed.
+	fallback io.Writer
+}
+
+// Write writes p to the primary writer, or to the fallback writer if the primary one returns an error.
+// A write only fails if both writers fail.
+func (w *fallbackWriter) Write(p []byte) (int, error) {
+	if n, err := w.primary.Write(p); err == nil {
+		return n, nil
+	}
+	return w.fallback.Write(p)
+}
+
+// SetFallbackOutput sets the writer that receives the entries an output fails to write, typically os.Stderr,
+// so a failing destination such as a network syslog target does not make entries vanish silently.
+// It applies to the outputs set with SetOutput and its variants as well as to SplitOutput and SetSyslogOutput.
+// A nil writer removes the fallback.
+func (l *Logger) SetFallbackOutput(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.fallback = w
+	l.levelOutputs.setFallback(w)
+	l.applyOutputs()
+}
+
+// flusher is implemented by buffered writers such as *bufio.Writer.
+type flusher interface {
+	Flush() error
+}
+
+// Flush flushes every configured writer that buffers its output, including the SplitOutput writers and the TCP
+// output, and is a no-op for plain writers.
+// Call it before the process exits so no pending entries are lost.
+func (l *Logger) Flush() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var errs []error
+	// Write the entries still queued for the background goroutine before flushing the writers below it.
+	if l.async != nil {
+		if err := l.async.Flush(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, w := range l.outputs {
+		if f, ok := w.(flusher); ok {
+			if err := f.Flush(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	// The SplitOutput writers may buffer too.
+	for _, w := range l.levelOutputs.get() {
+		if f, ok := w.(flusher); ok {
+			if err := f.Flush(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close releases the resources held by the logger: it stops the sampler and reports pending repeats, writes the entries queued for the
+// asynchronous writer and stops its goroutine, flushes the outputs, and closes the writers created by SetFileOutput
+// and SetBatchOutput and the syslog connection. All errors are joined into the returned one.
+// Afterwards the logger writes synchronously to os.Stderr, and calling Close again is a no-op.
+func (l *Logger) Close() error {
+	// Stop the sampler and the deduplication first, their last summaries still need the outputs.
+	if s := l.sampler.Swap(nil); s != nil {
+		s.close()
+		s.wait()
+	}
+	if d := l.dedup.Swap(nil); d != nil {
+		d.close()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var errs []error
+	if l.async != nil {
+		if err := l.async.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		l.async = nil
+	}
+	l.asyncSize = 0
+
+	for _, w := range l.outputs {
+		if f, ok := w.(flusher); ok {
+			if err := f.Flush(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	for _, f := range l.files {
+		if err := f.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	l.files = nil
+
+	// Stop routing entries to the SplitOutput and syslog writers, only os.Stderr receives them from now on.
+	l.levelOutputs.set(nil)
+	if l.syslog != nil {
+		if err := l.syslog.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		l.syslog = nil
+	}
+
+	// Nothing released above may be written to again.
+	l.outputs = []io.Writer{os.Stderr}
+	l.applyOutputs()
+	return errors.Join(errs...)
+}
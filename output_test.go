@@ -0,0 +1,202 @@
+package flogger
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lineSink collects the batches of a BatchWriter.
+type lineSink struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (s *lineSink) write(batch [][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, line := range batch {
+		s.lines = append(s.lines, string(line))
+	}
+	return nil
+}
+
+func (s *lineSink) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return strings.Join(s.lines, "")
+}
+
+func TestFatalFlushesBatchOutput(t *testing.T) {
+	l := New()
+	l.SetColors(false)
+	sink := &lineSink{}
+	// Neither the batch size nor the interval is ever reached, only a flush sends the lines.
+	l.SetBatchOutput(sink.write, 100, time.Hour)
+	defer l.Close()
+
+	var atExit string
+	l.SetExitFunc(func(int) { atExit = sink.String() })
+
+	l.Info("before")
+	l.Fatal("crash")
+
+	if !strings.Contains(atExit, "before") || !strings.Contains(atExit, "crash") {
+		t.Errorf("sink held %q when exiting, want both lines", atExit)
+	}
+}
+
+func TestFatalFlushesAsyncOutput(t *testing.T) {
+	l := New()
+	l.SetColors(false)
+	var mu sync.Mutex
+	var buf bytes.Buffer
+	l.SetOutput(writerFunc(func(p []byte) (int, error) {
+		// Hold every write back a little, so the entries are still queued when Fatal returns from logrus.
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		return buf.Write(p)
+	}))
+	l.EnableAsync(16)
+	defer l.Close()
+
+	var atExit string
+	l.SetExitFunc(func(int) {
+		mu.Lock()
+		defer mu.Unlock()
+		atExit = buf.String()
+	})
+
+	for i := 0; i < 10; i++ {
+		l.Info("queued")
+	}
+	l.Fatal("crash")
+
+	if n := strings.Count(atExit, "queued"); n != 10 || !strings.Contains(atExit, "crash") {
+		t.Errorf("output held %d queued lines and fatal=%v when exiting", n, strings.Contains(atExit, "crash"))
+	}
+}
+
+func TestFatalFlushesTCPOutput(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan string, 16)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			received <- scanner.Text()
+		}
+	}()
+
+	l := New()
+	if err := l.SetTCPOutput(listener.Addr().String()); err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	w := l.files[len(l.files)-1].(*tcpWriter)
+
+	var atExit uint64
+	l.SetExitFunc(func(int) { atExit = w.sent.Load() })
+
+	l.Info("before")
+	l.Fatal("crash")
+
+	if atExit != 2 {
+		t.Errorf("%d lines were sent when exiting, want 2", atExit)
+	}
+	for _, want := range []string{"before", "crash"} {
+		select {
+		case line := <-received:
+			if !strings.Contains(line, want) {
+				t.Errorf("peer received %q, want %q", line, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("peer did not receive %q", want)
+		}
+	}
+}
+
+// writerFunc adapts a function to io.Writer.
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
+// errWriter fails every write.
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestFallbackOutput(t *testing.T) {
+	l, _ := newTestLogger()
+	var fallback bytes.Buffer
+	l.SetOutput(errWriter{})
+	l.SetFallbackOutput(&fallback)
+
+	l.Info("rescued")
+
+	if !strings.Contains(fallback.String(), "INFO rescued") {
+		t.Errorf("fallback got %q, want the entry", fallback.String())
+	}
+}
+
+func TestFallbackOutputUnusedWhilePrimaryWorks(t *testing.T) {
+	l, primary := newTestLogger()
+	var fallback bytes.Buffer
+	l.SetFallbackOutput(&fallback)
+
+	l.Info("delivered")
+
+	if !strings.Contains(primary.String(), "delivered") || fallback.Len() != 0 {
+		t.Errorf("primary got %q and fallback %q, want only the primary", primary.String(), fallback.String())
+	}
+}
+
+func TestFallbackOutputForLevelOutputs(t *testing.T) {
+	l, _ := newTestLogger()
+	var fallback bytes.Buffer
+	l.SetOutput(io.Discard)
+	l.levelOutputs.set(map[logrus.Level]io.Writer{logrus.ErrorLevel: errWriter{}})
+	l.SetFallbackOutput(&fallback)
+
+	l.Error("routed")
+
+	if !strings.Contains(fallback.String(), "ERROR routed") {
+		t.Errorf("fallback got %q, want the routed entry", fallback.String())
+	}
+}
+
+func TestCloseStopsSplitOutput(t *testing.T) {
+	l := New()
+	l.SplitOutput()
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Entries would be written twice otherwise, by the level writers and by os.Stderr.
+	if writers := l.levelOutputs.get(); len(writers) != 0 {
+		t.Errorf("level writers after Close = %v, want none", writers)
+	}
+}
@@ -0,0 +1,45 @@
+package flogger
+
+import (
+	"github.com/sirupsen/logrus"
+	"runtime/debug"
+)
+
+// recovery fields added by RecoverAndLog.
+const (
+	// PanicField holds the value the goroutine panicked with.
+	PanicField = "panic"
+	// StackField holds the stack trace of the panicking goroutine.
+	StackField = "stack"
+)
+
+// RecoverAndLog recovers a panic and logs it at the Error level with the panic value and stack trace as fields.
+// It must be deferred directly, e.g. at the top of a worker goroutine:
+//
+//	defer logger.RecoverAndLog()
+//
+// The panic is swallowed unless SetRepanic(true) was called, in which case it continues after being logged.
+func (l *Logger) RecoverAndLog() {
+	// recover only works when called directly by the deferred function.
+	if r := recover(); r != nil {
+		l.logRecovered(r)
+	}
+}
+
+// SetRepanic makes RecoverAndLog continue panicking after logging when enabled, instead of swallowing the panic.
+func (l *Logger) SetRepanic(enabled bool) {
+	l.repanic.Store(enabled)
+}
+
+// logRecovered logs the recovered value r with the current stack and re-panics if configured to.
+func (l *Logger) logRecovered(r interface{}) {
+	entry := l.newEntry().WithFields(logrus.Fields{
+		PanicField: r,
+		StackField: string(debug.Stack()),
+	})
+	l.logf(entry, logrus.ErrorLevel, "recovered from panic: %v", []interface{}{r})
+
+	if l.repanic.Load() {
+		panic(r)
+	}
+}
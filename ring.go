@@ -0,0 +1,85 @@
+package flogger
+
+import (
+	"io"
+	"sync"
+)
+
+// ringBuffer is an output keeping the most recent formatted entries in memory, see EnableRingBuffer.
+type ringBuffer struct {
+	// mu guards the fields below.
+	mu sync.Mutex
+	// lines holds the kept entries; once full, next is the oldest one.
+	lines [][]byte
+	// next is the index the next entry is stored at.
+	next int
+	// full reports whether lines has wrapped around, so every slot holds an entry.
+	full bool
+}
+
+// newRingBuffer creates a ringBuffer keeping the last capacity entries.
+func newRingBuffer(capacity int) *ringBuffer {
+	return &ringBuffer{lines: make([][]byte, capacity)}
+}
+
+// Write stores a copy of p as the most recent entry, overwriting the oldest one once the buffer is full.
+func (r *ringBuffer) Write(p []byte) (int, error) {
+	// The logger reuses its buffer, so keep a copy.
+	line := append([]byte(nil), p...)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lines[r.next] = line
+	r.next++
+	if r.next == len(r.lines) {
+		r.next = 0
+		r.full = true
+	}
+	return len(p), nil
+}
+
+// dump writes the kept entries to w, oldest first. The entries are copied first so a slow w does not block logging.
+func (r *ringBuffer) dump(w io.Writer) error {
+	r.mu.Lock()
+	var lines [][]byte
+	if r.full {
+		lines = append(lines, r.lines[r.next:]...)
+	}
+	lines = append(lines, r.lines[:r.next]...)
+	r.mu.Unlock()
+
+	for _, line := range lines {
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnableRingBuffer keeps the last capacity formatted entries in memory, in addition to writing them to the outputs,
+// so DumpRecent can serve them, e.g. from a /debug/logs handler. Enabling it again starts an empty buffer,
+// and a capacity of zero or less disables it.
+func (l *Logger) EnableRingBuffer(capacity int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.ring = nil
+	if capacity > 0 {
+		l.ring = newRingBuffer(capacity)
+	}
+	l.applyOutputs()
+}
+
+// DumpRecent writes the entries kept by EnableRingBuffer to w, oldest first, formatted as they were written
+// to the outputs. It writes nothing if the ring buffer is disabled.
+func (l *Logger) DumpRecent(w io.Writer) error {
+	l.mu.Lock()
+	ring := l.ring
+	l.mu.Unlock()
+
+	if ring == nil {
+		return nil
+	}
+	return ring.dump(w)
+}
@@ -0,0 +1,222 @@
+package flogger
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// sampler lets at most n identical messages through per interval and reports how many it suppressed.
+type sampler struct {
+	// logger receives the summaries of suppressed messages.
+	logger *Logger
+	// n is the number of identical messages let through per interval.
+	n int
+	// interval is the length of a sampling window.
+	interval time.Duration
+	// stop ends the goroutine closing the windows.
+	stop chan struct{}
+	// done is closed once the goroutine has logged its last summaries and returned.
+	done chan struct{}
+
+	// mu guards counts.
+	mu sync.Mutex
+	// counts tracks the messages seen in the current window, keyed by message.
+	counts map[string]*sampleCount
+}
+
+// sampleCount tracks how often a message was seen in the current window.
+type sampleCount struct {
+	// level is the level the message was last logged at, used for its summary.
+	level logrus.Level
+	// seen is the number of times the message was logged in the window.
+	seen int
+}
+
+// newSampler creates a sampler for the logger and starts the goroutine closing its windows.
+func newSampler(logger *Logger, n int, interval time.Duration) *sampler {
+	s := &sampler{
+		logger:   logger,
+		n:        n,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		counts:   make(map[string]*sampleCount),
+	}
+	go s.run()
+	return s
+}
+
+// allow counts msg and reports whether it is still within the limit of the current window.
+func (s *sampler) allow(level logrus.Level, msg string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.counts[msg]
+	if !ok {
+		c = &sampleCount{}
+		s.counts[msg] = c
+	}
+	c.level = level
+	c.seen++
+	return c.seen <= s.n
+}
+
+// run closes a window every interval until stopped.
+func (s *sampler) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.closeWindow()
+		case <-s.stop:
+			s.closeWindow()
+			return
+		}
+	}
+}
+
+// closeWindow starts a new window and logs a summary for every message suppressed in the previous one.
+func (s *sampler) closeWindow() {
+	s.mu.Lock()
+	counts := s.counts
+	s.counts = make(map[string]*sampleCount, len(counts))
+	s.mu.Unlock()
+
+	for msg, c := range counts {
+		if suppressed := c.seen - s.n; suppressed > 0 {
+			// A summary must never exit or panic inside this goroutine, so it is logged at Error level at most.
+			level := c.level
+			if level < logrus.ErrorLevel {
+				level = logrus.ErrorLevel
+			}
+			// Log through logrus directly, the summary itself must not be sampled.
+			s.logger.log.WithField("message", msg).Logf(level, "suppressed %d identical messages in the last %s", suppressed, s.interval)
+		}
+	}
+}
+
+// close stops the sampler, logging the summaries of its last window.
+// It returns without waiting for them; use wait to do so.
+func (s *sampler) close() {
+	close(s.stop)
+}
+
+// wait blocks until the stopped sampler has logged the summaries of its last window.
+func (s *sampler) wait() {
+	<-s.done
+}
+
+// SetSampling limits identical messages to n per interval. Messages over the limit are dropped,
+// and when a window closes a summary reporting how many were suppressed is logged at their level.
+// Fatal and Panic entries are never sampled.
+// An n or interval of zero or less turns sampling off again.
+func (l *Logger) SetSampling(n int, interval time.Duration) {
+	var s *sampler
+	if n > 0 && interval > 0 {
+		s = newSampler(l, n, interval)
+	}
+	if previous := l.sampler.Swap(s); previous != nil {
+		previous.close()
+	}
+}
+
+// tickSampler lets the first messages with a key through in every interval, then one in every thereafter,
+// like zap's sampler. Counting costs a lock and a map lookup, and the counts are dropped wholesale when the
+// interval elapses, so there is no goroutine and the map only holds the keys of the current interval.
+type tickSampler struct {
+	// first is the number of messages with a key let through per interval.
+	first int
+	// thereafter lets every thereafter-th message through once first is exceeded, none if zero or less.
+	thereafter int
+	// interval is the length of a counting window.
+	interval time.Duration
+
+	// mu guards the fields below.
+	mu sync.Mutex
+	// start is the time the current window started at.
+	start time.Time
+	// counts tracks the messages seen in the current window, keyed by format string or message.
+	counts map[string]int
+}
+
+// allow counts key and reports whether its message is let through.
+func (t *tickSampler) allow(key string) bool {
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now.Sub(t.start) >= t.interval {
+		t.start = now
+		t.counts = make(map[string]int, len(t.counts))
+	}
+	t.counts[key]++
+	n := t.counts[key]
+	if n <= t.first {
+		return true
+	}
+	return t.thereafter > 0 && (n-t.first)%t.thereafter == 0
+}
+
+// SetSamplerTick lets the first messages with the same format string through in every interval, then only
+// one in every thereafter, e.g. SetSamplerTick(100, 1000, time.Second) for the first 100 and every 1000th after.
+// Messages logged without a format string, such as with Infoln or Infow, are counted by their message.
+// Dropped messages are not summarized, unlike with SetSampling. An interval of zero or less turns it off again.
+func (l *Logger) SetSamplerTick(first, thereafter int, interval time.Duration) {
+	var t *tickSampler
+	if interval > 0 {
+		t = &tickSampler{first: first, thereafter: thereafter, interval: interval}
+	}
+	l.tickSampler.Store(t)
+}
+
+// levelSampler lets entries through with a fixed probability per level, independently of their message.
+type levelSampler struct {
+	// rates holds the probability of emitting an entry, indexed by level; levels without a rate are always emitted.
+	rates [logrus.TraceLevel + 1]float64
+}
+
+// allow reports whether an entry at level is let through. Fatal and Panic entries have side effects and always are.
+func (s *levelSampler) allow(level logrus.Level) bool {
+	if level <= logrus.FatalLevel || int(level) >= len(s.rates) {
+		return true
+	}
+	rate := s.rates[level]
+	return rate >= 1 || rand.Float64() < rate
+}
+
+// SetLevelSampling emits the entries of each listed level with the given probability, e.g.
+// SetLevelSampling(map[string]float64{"debug": 0.01, "info": 0.1}) keeps about one Debug entry in a hundred and
+// one Info entry in ten. Unlisted levels, Error and above by default, are always emitted, and Fatal and Panic
+// entries are never dropped. Dropped entries are not summarized. It returns an error for unknown levels or rates
+// outside [0, 1]; an empty or nil map turns per-level sampling off again.
+func (l *Logger) SetLevelSampling(rates map[string]float64) error {
+	if len(rates) == 0 {
+		l.levelSampler.Store(nil)
+		return nil
+	}
+
+	s := &levelSampler{}
+	for i := range s.rates {
+		s.rates[i] = 1
+	}
+	for name, rate := range rates {
+		level, err := logrus.ParseLevel(name)
+		if err != nil {
+			return err
+		}
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("flogger: sampling rate %v of level %q is not between 0 and 1", rate, name)
+		}
+		s.rates[level] = rate
+	}
+	l.levelSampler.Store(s)
+	return nil
+}
@@ -0,0 +1,64 @@
+package flogger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamplingNeverDropsFatal(t *testing.T) {
+	l := New()
+	var buf bytes.Buffer
+	l.SetOutput(&buf)
+	exits := 0
+	l.SetExitFunc(func(int) { exits++ })
+	l.SetSampling(1, time.Hour)
+	defer l.Close()
+
+	for i := 0; i < 3; i++ {
+		l.Fatal("fatal")
+	}
+	if exits != 3 {
+		t.Errorf("exits = %d, want 3", exits)
+	}
+	if n := strings.Count(buf.String(), "fatal"); n != 3 {
+		t.Errorf("logged %d fatal entries, want 3", n)
+	}
+}
+
+func TestSamplingNeverDropsPanic(t *testing.T) {
+	l := New()
+	l.SetOutput(&bytes.Buffer{})
+	l.SetSampling(1, time.Hour)
+	defer l.Close()
+
+	for i := 0; i < 3; i++ {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Panic call %d returned normally", i+1)
+				}
+			}()
+			l.Panic("panic")
+		}()
+	}
+}
+
+func TestSamplingSummaryLevel(t *testing.T) {
+	l := New()
+	var buf bytes.Buffer
+	l.SetOutput(&buf)
+	l.SetSampling(1, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		l.Warn("repeated")
+	}
+	// Close logs the summary of the last window.
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "suppressed 2 identical messages") {
+		t.Errorf("output %q lacks the summary", buf.String())
+	}
+}
@@ -0,0 +1,132 @@
+package flogger
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"log/slog"
+	"strings"
+)
+
+// slogHandler is a slog.Handler that routes records through a Logger's logrus logger and formatter.
+type slogHandler struct {
+	// logger is the Logger the records are emitted with.
+	logger *Logger
+	// fields holds the attributes added with WithAttrs, already qualified with their group.
+	fields logrus.Fields
+	// groups holds the names of the groups opened with WithGroup, outermost first.
+	groups []string
+}
+
+// SlogHandler returns a slog.Handler that emits records through this logger, e.g.
+//
+//	slog.SetDefault(slog.New(logger.SlogHandler()))
+//
+// Attributes become fields, and grouped attributes are qualified with their group names joined by dots (e.g. "db.query").
+func (l *Logger) SlogHandler() slog.Handler {
+	return &slogHandler{logger: l}
+}
+
+// Enabled reports whether records at the given level would be emitted by the logger.
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.enabled(slogToLogrusLevel(level))
+}
+
+// Handle emits the record with the handler's attributes and the record's own attributes as fields.
+func (h *slogHandler) Handle(ctx context.Context, record slog.Record) error {
+	fields := make(logrus.Fields, len(h.fields)+record.NumAttrs())
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	prefix := groupPrefix(h.groups)
+	record.Attrs(func(attr slog.Attr) bool {
+		addSlogAttr(fields, prefix, attr)
+		return true
+	})
+
+	entry := h.logger.newEntry().WithContext(ctx).WithFields(fields).WithTime(record.Time)
+	h.logger.write(entry, slogToLogrusLevel(record.Level), record.Message)
+	return nil
+}
+
+// WithAttrs returns a handler that adds the given attributes, qualified with the current groups, to every record.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	fields := make(logrus.Fields, len(h.fields)+len(attrs))
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	prefix := groupPrefix(h.groups)
+	for _, attr := range attrs {
+		addSlogAttr(fields, prefix, attr)
+	}
+	return &slogHandler{logger: h.logger, fields: fields, groups: h.groups}
+}
+
+// WithGroup returns a handler that qualifies the attributes added afterwards with the given group name.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	// slog requires an empty group name to be ignored.
+	if name == "" {
+		return h
+	}
+
+	groups := make([]string, len(h.groups), len(h.groups)+1)
+	copy(groups, h.groups)
+	return &slogHandler{logger: h.logger, fields: h.fields, groups: append(groups, name)}
+}
+
+// groupPrefix returns the key prefix for the given groups, e.g. "http.request." for ["http", "request"].
+func groupPrefix(groups []string) string {
+	if len(groups) == 0 {
+		return ""
+	}
+	return strings.Join(groups, ".") + "."
+}
+
+// addSlogAttr adds attr to fields under prefix, flattening group attributes into dotted keys.
+func addSlogAttr(fields logrus.Fields, prefix string, attr slog.Attr) {
+	// Resolve LogValuer values before inspecting the kind.
+	attr.Value = attr.Value.Resolve()
+
+	// slog requires empty attributes to be ignored.
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		groupAttrs := attr.Value.Group()
+		// An empty group is ignored, and a group without a key is inlined.
+		if len(groupAttrs) == 0 {
+			return
+		}
+		groupPrefix := prefix
+		if attr.Key != "" {
+			groupPrefix = prefix + attr.Key + "."
+		}
+		for _, groupAttr := range groupAttrs {
+			addSlogAttr(fields, groupPrefix, groupAttr)
+		}
+		return
+	}
+
+	fields[prefix+attr.Key] = attr.Value.Any()
+}
+
+// slogToLogrusLevel maps a slog level to the closest logrus level.
+// Levels below slog.LevelDebug map to Trace, and levels above slog.LevelError map to Error.
+func slogToLogrusLevel(level slog.Level) logrus.Level {
+	switch {
+	case level < slog.LevelDebug:
+		return logrus.TraceLevel
+	case level < slog.LevelInfo:
+		return logrus.DebugLevel
+	case level < slog.LevelWarn:
+		return logrus.InfoLevel
+	case level < slog.LevelError:
+		return logrus.WarnLevel
+	default:
+		return logrus.ErrorLevel
+	}
+}
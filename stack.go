@@ -0,0 +1,157 @@
+package flogger
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// StackTraceField is the field under which SetStackTraceLevel attaches the stack trace.
+const StackTraceField = "stacktrace"
+
+// FramesField is the field under which SetStructuredStack attaches the stack frames.
+const FramesField = "frames"
+
+// StackFrame is one frame of the stack attached by SetStructuredStack, serialized as {"func", "file", "line"} in JSON.
+type StackFrame struct {
+	// Func is the fully qualified function name.
+	Func string `json:"func"`
+	// File is the full path of the source file.
+	File string `json:"file"`
+	// Line is the line number.
+	Line int `json:"line"`
+}
+
+// String renders the frame as "function file:line", the form text output shows.
+func (f StackFrame) String() string {
+	return fmt.Sprintf("%s %s:%d", f.Func, f.File, f.Line)
+}
+
+// maximumStackDepth restricts how many frames a captured stack trace holds.
+const maximumStackDepth = 64
+
+// stackTraceHook attaches a stack trace to every entry at or above its level while enabled.
+type stackTraceHook struct {
+	// enabled turns the hook on; it is off by default because capturing the stack costs time on every call.
+	enabled atomic.Bool
+	// level is the least severe level entries get a stack trace at.
+	level atomic.Uint32
+	// structured attaches the stack frames to Error, Fatal and Panic entries, independently of enabled.
+	structured atomic.Bool
+}
+
+// Levels returns all levels, the threshold can change at any time and is checked by Fire.
+func (h *stackTraceHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire attaches the stack trace of the entry's error if it carries one, as errors from github.com/pkg/errors do,
+// or else the current stack starting at the user's call site. Entries that already carry a stack are left alone.
+// With structured stacks, Error and more severe entries also get the current frames.
+func (h *stackTraceHook) Fire(entry *logrus.Entry) error {
+	if h.structured.Load() && entry.Level <= logrus.ErrorLevel {
+		if _, ok := entry.Data[FramesField]; !ok {
+			entry.Data[FramesField] = captureStackFrames()
+		}
+	}
+
+	if !h.enabled.Load() || entry.Level > logrus.Level(h.level.Load()) {
+		return nil
+	}
+	if _, ok := entry.Data[StackField]; ok {
+		return nil
+	}
+	if _, ok := entry.Data[StackTraceField]; ok {
+		return nil
+	}
+
+	if err, ok := entry.Data[ErrorField].(error); ok {
+		if stack, ok := errorStackTrace(err); ok {
+			entry.Data[StackTraceField] = stack
+			return nil
+		}
+	}
+	entry.Data[StackTraceField] = captureStackTrace()
+	return nil
+}
+
+// errorStackTrace returns the stack trace recorded by the innermost error of err's chain with a StackTrace method,
+// such as the errors of github.com/pkg/errors, rendered with %+v. The method is looked up by name so that no
+// particular errors package has to be imported.
+func errorStackTrace(err error) (string, bool) {
+	var stack string
+	found := false
+	for ; err != nil; err = errors.Unwrap(err) {
+		method := reflect.ValueOf(err).MethodByName("StackTrace")
+		if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+			continue
+		}
+		stack = strings.TrimPrefix(fmt.Sprintf("%+v", method.Call(nil)[0].Interface()), "\n")
+		found = true
+	}
+	return stack, found
+}
+
+// captureStackTrace returns the current stack from the first frame outside flogger, logrus and the standard log
+// packages, one "function\n\tfile:line" pair per frame like runtime/debug.Stack.
+func captureStackTrace() string {
+	var b strings.Builder
+	for _, frame := range captureStackFrames() {
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Func, frame.File, frame.Line)
+	}
+	return b.String()
+}
+
+// captureStackFrames returns the frames of the current stack from the first frame outside flogger, logrus and
+// the standard log packages.
+func captureStackFrames() []StackFrame {
+	pcs := make([]uintptr, maximumStackDepth)
+	// Skip runtime.Callers and captureStackFrames itself; the callers inside flogger are skipped below.
+	depth := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:depth])
+
+	var stack []StackFrame
+	userFrames := false
+	for {
+		frame, more := frames.Next()
+		// Skip the frames leading to the user's call site.
+		if userFrames || !isInternalFrame(frame.Function) {
+			userFrames = true
+			stack = append(stack, StackFrame{Func: frame.Function, File: frame.File, Line: frame.Line})
+		}
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+// SetStackTraceLevel attaches a stack trace as the stacktrace field to every entry at or above the given level,
+// e.g. "error". If the entry's error records a stack trace, as the errors of github.com/pkg/errors do, that one is
+// used instead of the current stack. It is off by default because of the cost, and an empty level turns it off again.
+// It returns an error for unknown levels.
+func (l *Logger) SetStackTraceLevel(level string) error {
+	if level == "" {
+		l.stackTrace.enabled.Store(false)
+		return nil
+	}
+
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	l.stackTrace.level.Store(uint32(lvl))
+	l.stackTrace.enabled.Store(true)
+	return nil
+}
+
+// SetStructuredStack attaches the current stack to every Error, Fatal and Panic entry as the frames field,
+// a slice of StackFrame serialized as [{"func": ..., "file": ..., "line": ...}] in JSON, which log UIs parse more
+// easily than the stacktrace string of SetStackTraceLevel. It is off by default because of the cost.
+func (l *Logger) SetStructuredStack(enabled bool) {
+	l.stackTrace.structured.Store(enabled)
+}
@@ -0,0 +1,33 @@
+package flogger
+
+import (
+	"errors"
+)
+
+// ErrSyslogUnsupported is returned by SetSyslogOutput on platforms without syslog support.
+var ErrSyslogUnsupported = errors.New("flogger: syslog is not supported on this platform")
+
+// SetSyslogOutput sends entries to a syslog daemon instead of the configured writers, with a priority matching
+// each entry's level: Panic→LOG_EMERG, Fatal→LOG_CRIT, Error→LOG_ERR, Warn→LOG_WARNING, Info→LOG_INFO,
+// Debug and Trace→LOG_DEBUG (facility LOG_USER). An empty network and addr connect to the local daemon.
+// It stays in effect until SetOutput or SetOutputs is called, and returns ErrSyslogUnsupported on Windows and Plan 9.
+func (l *Logger) SetSyslogOutput(network, addr, tag string) error {
+	writers, conn, err := dialSyslog(network, addr, tag)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Close the connection of a previous call.
+	if l.syslog != nil {
+		_ = l.syslog.Close()
+	}
+	l.syslog = conn
+
+	l.outputs = nil
+	l.applyOutputs()
+	l.levelOutputs.set(writers)
+	return nil
+}
@@ -0,0 +1,21 @@
+//go:build windows || plan9
+
+package flogger
+
+import (
+	"github.com/sirupsen/logrus"
+	"io"
+)
+
+// syslogPriorityWriter is never instantiated on platforms without syslog support.
+type syslogPriorityWriter struct{}
+
+// Write never succeeds, as there is no syslog to write to.
+func (w *syslogPriorityWriter) Write([]byte) (int, error) {
+	return 0, ErrSyslogUnsupported
+}
+
+// dialSyslog always fails with ErrSyslogUnsupported.
+func dialSyslog(string, string, string) (map[logrus.Level]io.Writer, io.Closer, error) {
+	return nil, nil, ErrSyslogUnsupported
+}
@@ -0,0 +1,43 @@
+//go:build !windows && !plan9
+
+package flogger
+
+import (
+	"github.com/sirupsen/logrus"
+	"io"
+	"log/syslog"
+)
+
+// syslogPriorityWriter writes every message to syslog with a fixed priority.
+type syslogPriorityWriter struct {
+	// write sends a message with the writer's priority, e.g. (*syslog.Writer).Err.
+	write func(msg string) error
+}
+
+// Write sends p to syslog and reports the whole of p as written.
+func (w *syslogPriorityWriter) Write(p []byte) (int, error) {
+	if err := w.write(string(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// dialSyslog connects to the syslog daemon and returns a writer for each level, plus the connection to close.
+func dialSyslog(network, addr, tag string) (map[logrus.Level]io.Writer, io.Closer, error) {
+	conn, err := syslog.Dial(network, addr, syslog.LOG_USER|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	debug := &syslogPriorityWriter{write: conn.Debug}
+	writers := map[logrus.Level]io.Writer{
+		logrus.PanicLevel: &syslogPriorityWriter{write: conn.Emerg},
+		logrus.FatalLevel: &syslogPriorityWriter{write: conn.Crit},
+		logrus.ErrorLevel: &syslogPriorityWriter{write: conn.Err},
+		logrus.WarnLevel:  &syslogPriorityWriter{write: conn.Warning},
+		logrus.InfoLevel:  &syslogPriorityWriter{write: conn.Info},
+		logrus.DebugLevel: debug,
+		logrus.TraceLevel: debug,
+	}
+	return writers, conn, nil
+}
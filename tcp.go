@@ -0,0 +1,220 @@
+package flogger
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// settings of the writer created by SetTCPOutput.
+const (
+	// tcpBufferSize is how many entries are buffered while the connection is down; further entries are dropped.
+	tcpBufferSize = 1024
+	// tcpDialTimeout bounds every connection attempt.
+	tcpDialTimeout = 5 * time.Second
+	// tcpWriteTimeout bounds every write, so a stalled peer is treated like a broken connection.
+	tcpWriteTimeout = 5 * time.Second
+	// tcpMinBackoff is the wait before the first reconnection attempt, doubled after every failed one.
+	tcpMinBackoff = 100 * time.Millisecond
+	// tcpMaxBackoff caps the wait between reconnection attempts.
+	tcpMaxBackoff = 30 * time.Second
+	// tcpFlushTimeout bounds how long Flush waits for the buffered lines to be sent.
+	tcpFlushTimeout = 5 * time.Second
+)
+
+// tcpLine is an item of a tcpWriter's buffer: a line to send, or a Flush waiting for the lines queued before it.
+type tcpLine struct {
+	// data is the line to send, terminated by a newline.
+	data []byte
+	// flushed is closed once every line queued before it was handled, nil for a line.
+	flushed chan struct{}
+}
+
+// tcpWriter sends every line over a TCP connection from a background goroutine, reconnecting with exponential
+// backoff when the connection fails. Lines are buffered meanwhile, and dropped once the buffer is full, so logging
+// never blocks on the network.
+type tcpWriter struct {
+	// addr is the address dialed, e.g. "logstash:5000".
+	addr string
+	// lines buffers the lines waiting to be sent and the pending Flush calls.
+	lines chan tcpLine
+	// stop is closed by Close to abandon reconnecting.
+	stop chan struct{}
+	// done is closed when the background goroutine has exited.
+	done chan struct{}
+	// dropped counts the lines discarded because the buffer was full.
+	dropped atomic.Uint64
+	// sent counts the lines written to a connection.
+	sent atomic.Uint64
+	// conn is the current connection, nil while disconnected. Only the background goroutine uses it once started.
+	conn net.Conn
+
+	// mu guards closed; Write holds it for reading so Close cannot close lines under a pending send.
+	mu sync.RWMutex
+	// closed reports whether Close was called.
+	closed bool
+}
+
+// newTCPWriter creates a tcpWriter for addr. Its background goroutine is started by start.
+func newTCPWriter(addr string) *tcpWriter {
+	return &tcpWriter{
+		addr:  addr,
+		lines: make(chan tcpLine, tcpBufferSize),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+}
+
+// dial connects to the address, replacing any previous connection.
+func (w *tcpWriter) dial() error {
+	conn, err := net.DialTimeout("tcp", w.addr, tcpDialTimeout)
+	if err != nil {
+		return err
+	}
+	w.conn = conn
+	return nil
+}
+
+// start starts the background goroutine sending the buffered lines.
+func (w *tcpWriter) start() {
+	go w.run()
+}
+
+// Write queues a copy of p, terminated by a newline, and never fails; p is dropped if the buffer is full
+// or the writer is closed.
+func (w *tcpWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		w.dropped.Add(1)
+		return len(p), nil
+	}
+
+	// The logger reuses its buffer, so keep a copy.
+	line := make([]byte, len(p), len(p)+1)
+	copy(line, p)
+	if len(line) == 0 || line[len(line)-1] != '\n' {
+		line = append(line, '\n')
+	}
+
+	select {
+	case w.lines <- tcpLine{data: line}:
+	default:
+		w.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Close sends the buffered lines if the connection is up, stops reconnecting and closes the connection.
+// It is safe to call more than once.
+func (w *tcpWriter) Close() error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.lines)
+		close(w.stop)
+	}
+	w.mu.Unlock()
+
+	<-w.done
+	return nil
+}
+
+// Flush waits until the lines buffered so far have been sent, or dropped because Close abandoned reconnecting,
+// for at most tcpFlushTimeout, so a Fatal entry reaches the peer before the process exits.
+func (w *tcpWriter) Flush() error {
+	timeout := time.NewTimer(tcpFlushTimeout)
+	defer timeout.Stop()
+
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return nil
+	}
+	flushed := make(chan struct{})
+	select {
+	case w.lines <- tcpLine{flushed: flushed}:
+	case <-timeout.C:
+		w.mu.RUnlock()
+		return fmt.Errorf("flogger: flush TCP output to %s: timed out after %s", w.addr, tcpFlushTimeout)
+	}
+	// Close must not wait for this Flush, the background goroutine handles the marker either way.
+	w.mu.RUnlock()
+
+	select {
+	case <-flushed:
+		return nil
+	case <-timeout.C:
+		return fmt.Errorf("flogger: flush TCP output to %s: timed out after %s", w.addr, tcpFlushTimeout)
+	}
+}
+
+// run sends the buffered lines until lines is closed, then closes the connection.
+func (w *tcpWriter) run() {
+	defer close(w.done)
+
+	for line := range w.lines {
+		if line.flushed != nil {
+			close(line.flushed)
+			continue
+		}
+		w.send(line.data)
+	}
+	if w.conn != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+}
+
+// send writes line, reconnecting as often as needed. It gives up on the line once Close abandons reconnecting.
+func (w *tcpWriter) send(line []byte) {
+	backoff := tcpMinBackoff
+	for {
+		if w.conn == nil {
+			if err := w.dial(); err != nil {
+				select {
+				case <-w.stop:
+					w.dropped.Add(1)
+					return
+				case <-time.After(backoff):
+				}
+				backoff = min(2*backoff, tcpMaxBackoff)
+				continue
+			}
+		}
+
+		_ = w.conn.SetWriteDeadline(time.Now().Add(tcpWriteTimeout))
+		if _, err := w.conn.Write(line); err == nil {
+			w.sent.Add(1)
+			return
+		}
+		// The line may have been partially written; resend it whole on a new connection.
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+}
+
+// SetTCPOutput sends entries as newline-delimited JSON over a TCP connection to addr, e.g. a Logstash TCP input,
+// instead of the configured writers. It switches the format to JSON; call SetFormatter afterwards for another one.
+// Entries are sent from a background goroutine that reconnects with exponential backoff when the connection fails,
+// buffering up to 1024 entries meanwhile and dropping further ones, so logging never blocks on the network.
+// If the initial connection fails, its error is returned but the output is still installed and keeps retrying.
+// Flush waits up to five seconds for the buffered entries to be sent, and Close sends what is buffered and closes
+// the connection.
+func (l *Logger) SetTCPOutput(addr string) error {
+	w := newTCPWriter(addr)
+	err := w.dial()
+	w.start()
+
+	_ = l.SetFormatter(FormatJSON)
+	l.SetOutput(w)
+
+	// The logger created the writer, so it is the one closing it, see Close.
+	l.mu.Lock()
+	l.files = append(l.files, w)
+	l.mu.Unlock()
+	return err
+}
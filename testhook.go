@@ -0,0 +1,106 @@
+package flogger
+
+import (
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+// RecordedEntry is an entry recorded by a TestHook.
+type RecordedEntry struct {
+	// Level is the name of the entry's level, e.g. "error", or the name of a level registered with RegisterLevel.
+	Level string
+	// Message is the logged message.
+	Message string
+	// Fields holds the entry's fields, including the default and context fields but not the caller fields.
+	Fields map[string]interface{}
+	// Time is the time the entry was logged at.
+	Time time.Time
+}
+
+// TestHook records the entries emitted by a Logger so tests can inspect them instead of matching rendered text, e.g.
+//
+//	hook := logger.NewTestHook()
+//	defer hook.Remove()
+//	handle(request)
+//	if e := hook.LastEntry(); e == nil || e.Level != "error" {
+//		t.Fatalf("expected an error entry, got %+v", e)
+//	}
+//
+// It is safe for concurrent use.
+type TestHook struct {
+	// logger is the Logger the hook is installed on.
+	logger *Logger
+
+	// mu guards entries.
+	mu sync.Mutex
+	// entries holds the recorded entries, oldest first.
+	entries []RecordedEntry
+}
+
+// NewTestHook installs a TestHook on this logger, recording every entry that passes the level filtering
+// until Remove is called.
+func (l *Logger) NewTestHook() *TestHook {
+	hook := &TestHook{logger: l}
+	l.AddHook(hook)
+	return hook
+}
+
+// Levels returns all levels, the logger has already filtered the entries.
+func (h *TestHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire records a copy of the entry.
+func (h *TestHook) Fire(entry *logrus.Entry) error {
+	level := LevelName(entry)
+	fields := make(map[string]interface{}, len(entry.Data))
+	for k, v := range withoutLevelName(entry.Data) {
+		fields[k] = v
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries = append(h.entries, RecordedEntry{
+		Level:   level,
+		Message: entry.Message,
+		Fields:  fields,
+		Time:    entry.Time,
+	})
+	return nil
+}
+
+// Entries returns the recorded entries, oldest first.
+func (h *TestHook) Entries() []RecordedEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]RecordedEntry(nil), h.entries...)
+}
+
+// LastEntry returns the most recently recorded entry, nil if none was recorded.
+func (h *TestHook) LastEntry() *RecordedEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.entries) == 0 {
+		return nil
+	}
+	entry := h.entries[len(h.entries)-1]
+	return &entry
+}
+
+// Reset discards the recorded entries.
+func (h *TestHook) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries = nil
+}
+
+// Remove uninstalls the hook from its logger, so later entries are no longer recorded.
+// The entries recorded so far remain available.
+func (h *TestHook) Remove() {
+	h.logger.removeHook(h)
+}
@@ -0,0 +1,404 @@
+package flogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/mgutz/ansi"
+	"github.com/sirupsen/logrus"
+	prefixed "github.com/x-cray/logrus-prefixed-formatter"
+	"golang.org/x/term"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// prefixField is the field the prefixed formatter renders as a highlighted prefix before the message.
+const prefixField = "prefix"
+
+// styles of the header parts, matching the prefixed formatter's default color scheme.
+const (
+	// timestampStyle is the ansi style of timestamps.
+	timestampStyle = "black+h"
+	// prefixStyle is the ansi style of the prefix.
+	prefixStyle = "cyan"
+)
+
+// alignedLevelWidth is the width SetAlignMessages pads level labels to, long enough for NOTICE.
+const alignedLevelWidth = 6
+
+// relativeEpoch is the reference time of relative timestamps: the time of the first entry unless set explicitly.
+type relativeEpoch struct {
+	// start is the reference time, nil until the first entry or SetTimestampEpoch.
+	start atomic.Pointer[time.Time]
+}
+
+// since returns the epoch, initializing it to t if it is not set yet.
+func (e *relativeEpoch) since(t time.Time) time.Time {
+	e.start.CompareAndSwap(nil, &t)
+	return *e.start.Load()
+}
+
+// textFormatter renders the line header (timestamp, level, prefix and message) with the prefixed.TextFormatter
+// and the fields itself, so their order can be controlled.
+type textFormatter struct {
+	// header renders everything but the fields.
+	header *prefixed.TextFormatter
+	// traceHeader renders the header of Trace entries, nil to use header.
+	traceHeader *prefixed.TextFormatter
+	// levelColors overrides the default level colors of field keys.
+	levelColors map[logrus.Level]string
+	// levelNameColors maps registered level names to the style of their label and field keys.
+	levelNameColors map[string]string
+	// colors selects whether the fields are colored, like the header.
+	colors colorMode
+	// fieldOrder lists the keys rendered first, in this order.
+	fieldOrder []string
+	// trailingKeys lists the keys rendered last, in this order, unless they are part of fieldOrder.
+	trailingKeys []string
+	// prettyJSON holds the keys whose values are indented if they are valid JSON, nil if there are none.
+	prettyJSON map[string]bool
+	// epoch renders the time elapsed since it instead of the header's timestamp, nil for absolute timestamps.
+	epoch *relativeEpoch
+	// timestampFormat renders the timestamp in place of the header's timestamp with this layout, empty to leave it to the header.
+	timestampFormat string
+	// callerInPrefix renders the func and file fields after the timestamp, e.g. "[svc.Handle handler.go:42]".
+	callerInPrefix bool
+	// funcKey is the key of the func field.
+	funcKey string
+	// fileKey is the key of the file field.
+	fileKey string
+	// alignMessages left-justifies the level label to alignedLevelWidth characters.
+	alignMessages bool
+	// fieldSeparator is written before every field, empty for a single space.
+	fieldSeparator string
+	// jsonValues renders maps, structs, slices and arrays as JSON instead of with %+v.
+	jsonValues bool
+	// fieldKeyColor is the ansi style of field keys, empty for the level's color.
+	fieldKeyColor string
+	// fieldValueColor is the ansi style of field values, empty to leave them uncolored.
+	fieldValueColor string
+
+	// terminalOnce guards terminal, detected from the first entry's output like the prefixed formatter does.
+	terminalOnce sync.Once
+	// terminal reports whether the output is a terminal.
+	terminal bool
+}
+
+// Format renders the header through the prefixed formatter and appends the fields in the configured order.
+func (f *textFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	// Only hand the prefix to the prefixed formatter, the other fields are rendered below.
+	header := *entry
+	header.Data = make(logrus.Fields, 1)
+	if prefix, ok := entry.Data[prefixField]; ok {
+		header.Data[prefixField] = prefix
+	}
+	headerFormatter := f.header
+	if entry.Level == logrus.TraceLevel && f.traceHeader != nil {
+		headerFormatter = f.traceHeader
+	}
+	out, err := headerFormatter.Format(&header)
+	if err != nil {
+		return nil, err
+	}
+	out = f.relabelLevel(out, entry)
+
+	// Continue the header's line: drop its newline and append the fields.
+	b := &bytes.Buffer{}
+	f.writeTimestamp(b, entry)
+	if f.callerInPrefix {
+		f.writeCaller(b, entry)
+	}
+	b.Write(out[:len(out)-1])
+	keyColor, valueColor := f.keyColor(entry), f.valueColor(entry)
+	separator := f.fieldSeparator
+	if separator == "" {
+		separator = " "
+	}
+	for _, key := range f.orderedKeys(entry.Data) {
+		if f.prettyJSON[key] {
+			if indented, ok := indentJSON(entry.Data[key]); ok {
+				fmt.Fprintf(b, "%s%s=%s", separator, keyColor(key), valueColor(string(indented)))
+				continue
+			}
+		}
+		fmt.Fprintf(b, "%s%s=%s", separator, keyColor(key), valueColor(f.formatValue(entry.Data[key])))
+	}
+	b.WriteByte('\n')
+	return b.Bytes(), nil
+}
+
+// relabelLevel replaces the level label in the header rendered by the prefixed formatter with the label of the
+// registered level name, e.g. " INFO" with "NOTICE", and left-justifies it to alignedLevelWidth characters if
+// messages are aligned. The label is the first thing the header renders after the timestamp, so its first
+// occurrence is the one to replace.
+func (f *textFormatter) relabelLevel(header []byte, entry *logrus.Entry) []byte {
+	name, renamed := levelName(entry)
+	if !renamed && !f.alignMessages {
+		return header
+	}
+
+	// The prefixed formatter renders the Warn level as "WARN" and pads every label to five characters.
+	text := strings.ToUpper(entry.Level.String())
+	if entry.Level == logrus.WarnLevel {
+		text = "WARN"
+	}
+	label := fmt.Sprintf("%5s", text)
+
+	relabeled, style := text, f.headerLevelStyle(entry.Level)
+	if renamed {
+		relabeled, style = strings.ToUpper(name), f.levelNameStyle(entry, name)
+	}
+	if f.alignMessages {
+		relabeled = fmt.Sprintf("%-*s", alignedLevelWidth, relabeled)
+	} else {
+		relabeled = fmt.Sprintf("%5s", relabeled)
+	}
+
+	if f.colored(entry) {
+		label = ansi.Color(label, f.headerLevelStyle(entry.Level))
+		relabeled = ansi.Color(relabeled, style)
+	}
+	return bytes.Replace(header, []byte(label), []byte(relabeled), 1)
+}
+
+// headerLevelStyle returns the style the header renders the level label with: Trace shares the Debug style
+// unless it has its own color.
+func (f *textFormatter) headerLevelStyle(level logrus.Level) string {
+	if _, ok := f.levelColors[level]; !ok && level == logrus.TraceLevel {
+		level = logrus.DebugLevel
+	}
+	return levelStyle(f.levelColors, level)
+}
+
+// levelNameStyle returns the ansi style of the registered level name: its entry in levelNameColors if any,
+// otherwise the style of the entry's logrus level.
+func (f *textFormatter) levelNameStyle(entry *logrus.Entry, name string) string {
+	if style, ok := f.levelNameColors[name]; ok {
+		return style
+	}
+	return levelStyle(f.levelColors, entry.Level)
+}
+
+// writeTimestamp writes the timestamp the header does not render, styled like the prefixed formatter's timestamp:
+// the time elapsed between the epoch and the entry with relative timestamps, e.g. "[+1.234567ms] ",
+// or else the entry's time if a layout is set.
+func (f *textFormatter) writeTimestamp(b *bytes.Buffer, entry *logrus.Entry) {
+	var timestamp string
+	switch {
+	case f.epoch != nil:
+		timestamp = fmt.Sprintf("[+%s]", entry.Time.Sub(f.epoch.since(entry.Time)))
+	case f.timestampFormat != "":
+		timestamp = fmt.Sprintf("[%s]", entry.Time.Format(f.timestampFormat))
+	default:
+		return
+	}
+	if f.colored(entry) {
+		timestamp = ansi.Color(timestamp, timestampStyle)
+	}
+	b.WriteString(timestamp)
+	b.WriteByte(' ')
+}
+
+// writeCaller writes the entry's func and file fields, those of them it carries, e.g. "[svc.Handle handler.go:42] ".
+func (f *textFormatter) writeCaller(b *bytes.Buffer, entry *logrus.Entry) {
+	var parts []string
+	for _, key := range []string{f.funcKey, f.fileKey} {
+		if v, ok := entry.Data[key]; ok {
+			parts = append(parts, fmt.Sprint(v))
+		}
+	}
+	if len(parts) == 0 {
+		return
+	}
+
+	caller := "[" + strings.Join(parts, " ") + "]"
+	if f.colored(entry) {
+		caller = ansi.Color(caller, timestampStyle)
+	}
+	b.WriteString(caller)
+	b.WriteByte(' ')
+}
+
+// formatValue renders a field value with %+v, or as JSON if JSON values are enabled and the value is complex.
+// Values that cannot be marshalled fall back to %+v.
+func (f *textFormatter) formatValue(value interface{}) string {
+	if f.jsonValues && isComplexValue(value) {
+		if data, err := json.Marshal(value); err == nil {
+			return string(data)
+		}
+	}
+	return fmt.Sprintf("%+v", value)
+}
+
+// isComplexValue reports whether value is a map, struct, slice or array, or a pointer to one, that %+v renders
+// poorly. Errors, fmt.Stringers and byte slices render themselves and are not complex.
+func isComplexValue(value interface{}) bool {
+	switch value.(type) {
+	case error, fmt.Stringer, []byte, json.RawMessage:
+		return false
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Map, reflect.Struct, reflect.Slice, reflect.Array:
+		return true
+	default:
+		return false
+	}
+}
+
+// prettyJSONKeys returns the set of keys, or nil if there are none.
+func prettyJSONKeys(keys []string) map[string]bool {
+	if len(keys) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		set[key] = true
+	}
+	return set
+}
+
+// indentJSON returns value indented if it is a string or byte slice holding a JSON object or array.
+// Other values, including JSON scalars, are reported as not indentable and rendered as usual.
+func indentJSON(value interface{}) ([]byte, bool) {
+	var data []byte
+	switch v := value.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	case json.RawMessage:
+		data = v
+	default:
+		return nil, false
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || (data[0] != '{' && data[0] != '[') {
+		return nil, false
+	}
+
+	var b bytes.Buffer
+	if err := json.Indent(&b, data, "", "  "); err != nil {
+		return nil, false
+	}
+	return b.Bytes(), true
+}
+
+// orderedKeys returns the keys of data to render: the fieldOrder keys first, then the remaining keys sorted,
+// then the trailing keys. The prefix is part of the header and therefore skipped.
+func (f *textFormatter) orderedKeys(data logrus.Fields) []string {
+	keys := make([]string, 0, len(data))
+	placed := make(map[string]bool, len(f.fieldOrder)+len(f.trailingKeys)+1)
+	placed[prefixField] = true
+	placed[LevelNameField] = true
+	// The caller is part of the header too in that mode.
+	if f.callerInPrefix {
+		placed[f.funcKey] = true
+		placed[f.fileKey] = true
+	}
+
+	for _, key := range f.fieldOrder {
+		if _, ok := data[key]; ok && !placed[key] {
+			keys = append(keys, key)
+			placed[key] = true
+		}
+	}
+
+	// The trailing keys are placed after the sorted remainder.
+	var trailing []string
+	for _, key := range f.trailingKeys {
+		if _, ok := data[key]; ok && !placed[key] {
+			trailing = append(trailing, key)
+			placed[key] = true
+		}
+	}
+
+	rest := make([]string, 0, len(data))
+	for key := range data {
+		if !placed[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+
+	keys = append(keys, rest...)
+	return append(keys, trailing...)
+}
+
+// keyColor returns the function coloring field keys in the entry's level color, or leaving them as is without colors.
+func (f *textFormatter) keyColor(entry *logrus.Entry) func(string) string {
+	if !f.colored(entry) {
+		return func(s string) string { return s }
+	}
+	if f.fieldKeyColor != "" {
+		return ansi.ColorFunc(f.fieldKeyColor)
+	}
+	if name, ok := levelName(entry); ok {
+		return ansi.ColorFunc(f.levelNameStyle(entry, name))
+	}
+	return ansi.ColorFunc(levelStyle(f.levelColors, entry.Level))
+}
+
+// valueColor returns the function coloring field values in the value color, or leaving them as is without colors
+// or without a value color.
+func (f *textFormatter) valueColor(entry *logrus.Entry) func(string) string {
+	if f.fieldValueColor == "" || !f.colored(entry) {
+		return func(s string) string { return s }
+	}
+	return ansi.ColorFunc(f.fieldValueColor)
+}
+
+// colored reports whether the output is colored, following the same rules as the prefixed formatter.
+func (f *textFormatter) colored(entry *logrus.Entry) bool {
+	switch f.colors {
+	case colorsOn:
+		return true
+	case colorsOff:
+		return false
+	}
+
+	f.terminalOnce.Do(func() {
+		if entry.Logger != nil {
+			f.terminal = isTerminal(entry.Logger.Out)
+		}
+	})
+	return f.terminal
+}
+
+// levelStyle returns the ansi style of the level: its entry in colors if any, otherwise the prefixed formatter's
+// default color scheme.
+func levelStyle(colors map[logrus.Level]string, level logrus.Level) string {
+	if style, ok := colors[level]; ok {
+		return style
+	}
+	switch level {
+	case logrus.InfoLevel:
+		return "green"
+	case logrus.WarnLevel:
+		return "yellow"
+	case logrus.ErrorLevel, logrus.FatalLevel, logrus.PanicLevel:
+		return "red"
+	default:
+		return "blue"
+	}
+}
+
+// isTerminal reports whether w is a file attached to a terminal.
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(file.Fd()))
+}
@@ -0,0 +1,45 @@
+package flogger
+
+import (
+	"github.com/mgutz/ansi"
+	"strings"
+	"testing"
+)
+
+func TestPrefixRendering(t *testing.T) {
+	l, buf := newTestLogger()
+
+	l.WithPrefix("db").WithField("k", 1).Info("connected")
+	l.WithField("k", 1).WithPrefix("cache").Warn("miss")
+
+	out := buf.String()
+	for _, want := range []string{" INFO db: connected k=1\n", " WARN cache: miss k=1\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output %q lacks %q", out, want)
+		}
+	}
+	if strings.Contains(out, "prefix=") {
+		t.Errorf("output %q renders the prefix as a field", out)
+	}
+}
+
+func TestPrefixRenderingColored(t *testing.T) {
+	l, buf := newTestLogger()
+	l.SetColors(true)
+
+	l.WithPrefix("db").Info("connected")
+
+	if want := ansi.Color(" db:", prefixStyle) + " connected"; !strings.Contains(buf.String(), want) {
+		t.Errorf("output %q lacks the highlighted prefix %q", buf.String(), want)
+	}
+}
+
+func TestPrefixJSON(t *testing.T) {
+	l, buf := newJSONLogger(t)
+
+	l.WithPrefix("db").Info("connected")
+
+	if got := decodeJSON(t, buf.Bytes())[prefixField]; got != "db" {
+		t.Errorf("prefix = %v, want db", got)
+	}
+}
@@ -0,0 +1,64 @@
+package flogger
+
+import (
+	"bytes"
+	"github.com/sirupsen/logrus"
+	"io"
+	"log"
+)
+
+// levelWriter is an io.Writer that logs every line written to it at a fixed level.
+type levelWriter struct {
+	// logger is the Logger the lines are emitted with.
+	logger *Logger
+	// level is the level every line is logged at.
+	level logrus.Level
+}
+
+// Writer returns an io.Writer that logs each line written to it at the given level, which makes it possible
+// to bridge libraries expecting an io.Writer or a *log.Logger:
+//
+//	server := &http.Server{ErrorLog: log.New(logger.Writer("error"), "", 0)}
+//
+// Trailing newlines are trimmed and empty lines are dropped. Unknown level names fall back to Info.
+func (l *Logger) Writer(level string) io.Writer {
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		lvl = logrus.InfoLevel
+	}
+	return &levelWriter{logger: l, level: lvl}
+}
+
+// Write logs every non-empty line of p and always reports the whole of p as written.
+func (w *levelWriter) Write(p []byte) (int, error) {
+	// Skip the conversion entirely when the level is disabled.
+	if !w.logger.enabled(w.level) {
+		return len(p), nil
+	}
+
+	for _, line := range bytes.Split(p, []byte{'\n'}) {
+		line = bytes.TrimRight(line, "\r")
+		if len(line) == 0 {
+			continue
+		}
+		w.logger.write(nil, w.level, string(line))
+	}
+	return len(p), nil
+}
+
+// Std returns a *log.Logger whose output is logged at Info level, for code that expects the standard library's
+// logger. It adds no prefix or timestamp of its own, flogger renders those.
+func (l *Logger) Std() *log.Logger {
+	return log.New(l.Writer("info"), "", 0)
+}
+
+// SetAsStdLogDefault routes the output of the standard log package's default logger into this logger at Info level,
+// so existing log.Printf and log.Println calls flow through flogger. Unless preserveHeader is set, the default
+// logger's prefix and flags are cleared so its timestamp does not end up in the message next to flogger's.
+func (l *Logger) SetAsStdLogDefault(preserveHeader bool) {
+	if !preserveHeader {
+		log.SetPrefix("")
+		log.SetFlags(0)
+	}
+	log.SetOutput(l.Writer("info"))
+}